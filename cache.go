@@ -0,0 +1,228 @@
+package goladok3
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CacheConfig enables caching of GET responses from ladok, keyed by URL and Accept header
+type CacheConfig struct {
+	// Backend stores the responses, defaults to NewLRUCache(1000)
+	Backend CacheBackend
+	// TTL is how long a response from a service, like kataloginformation, is used without asking ladok.
+	// After that it is revalidated with If-None-Match and If-Modified-Since.
+	TTL map[string]time.Duration
+	// DefaultTTL is used for services missing in TTL, 0 means every use is revalidated
+	DefaultTTL time.Duration
+}
+
+// CacheEntry is a cached ladok response
+type CacheEntry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Expires is when the entry has to be revalidated with ladok
+	Expires time.Time
+}
+
+// CacheBackend stores cached responses, it has to be safe for concurrent use
+type CacheBackend interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// LRUCache is an in-memory CacheBackend holding a fixed number of entries
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewLRUCache returns an LRUCache evicting the least recently used entry beyond size entries
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// Get returns the entry of key
+func (l *LRUCache) Get(key string) (*CacheEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return element.Value.(*lruItem).entry, true
+}
+
+// Set stores entry as key
+func (l *LRUCache) Set(key string, entry *CacheEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.entries[key]; ok {
+		element.Value.(*lruItem).entry = entry
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(&lruItem{key: key, entry: entry})
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruItem).key)
+	}
+}
+
+// Delete removes key
+func (l *LRUCache) Delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.entries[key]; ok {
+		l.order.Remove(element)
+		delete(l.entries, key)
+	}
+}
+
+// Len returns the number of entries
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// responseCache is the client side of CacheConfig
+type responseCache struct {
+	backend    CacheBackend
+	ttl        map[string]time.Duration
+	defaultTTL time.Duration
+}
+
+func newResponseCache(config *CacheConfig) *responseCache {
+	if config == nil {
+		return nil
+	}
+	backend := config.Backend
+	if backend == nil {
+		backend = NewLRUCache(1000)
+	}
+	return &responseCache{
+		backend:    backend,
+		ttl:        config.TTL,
+		defaultTTL: config.DefaultTTL,
+	}
+}
+
+// serviceTTL returns the ttl of the service called in ctx
+func (r *responseCache) serviceTTL(ctx context.Context) time.Duration {
+	endpoint, _ := EndpointFromContext(ctx)
+	if ttl, ok := r.ttl[endpoint.Service]; ok {
+		return ttl
+	}
+	return r.defaultTTL
+}
+
+func cacheKey(req *http.Request) string {
+	return fmt.Sprintf("%s %s", req.URL.String(), req.Header.Get("Accept"))
+}
+
+// response returns entry as the reply to req
+func (e *CacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// sendCached answers a GET request from the cache while the entry is fresh, other methods drop the cached entry. Otherwise req is sent,
+// conditionally if there is a cached entry, and the cached body is returned if ladok replies 304 Not Modified.
+func (c *Client) sendCached(ctx context.Context, req *http.Request) (*http.Response, int, error) {
+	if c.cache == nil {
+		return c.send(ctx, req)
+	}
+	if req.Method != http.MethodGet {
+		resp, attempts, err := c.send(ctx, req)
+		// A successful write makes the cached reply of the same resource stale
+		if err == nil && resp.StatusCode < http.StatusMultipleChoices {
+			c.cache.backend.Delete(cacheKey(req))
+		}
+		return resp, attempts, err
+	}
+
+	key := cacheKey(req)
+	entry, cached := c.cache.backend.Get(key)
+	if cached {
+		if time.Now().Before(entry.Expires) {
+			return entry.response(req), 0, nil
+		}
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, attempts, err := c.send(ctx, req)
+	if err != nil {
+		return resp, attempts, err
+	}
+	ttl := c.cache.serviceTTL(ctx)
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		refreshed := *entry
+		refreshed.Expires = time.Now().Add(ttl)
+		c.cache.backend.Set(key, &refreshed)
+		return refreshed.response(req), attempts, nil
+
+	case resp.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, attempts, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Without a ttl or validators the entry could never be used
+		if ttl > 0 || resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "" {
+			c.cache.backend.Set(key, &CacheEntry{
+				StatusCode: resp.StatusCode,
+				Header:     resp.Header.Clone(),
+				Body:       body,
+				Expires:    time.Now().Add(ttl),
+			})
+		}
+	}
+
+	return resp, attempts, nil
+}
@@ -0,0 +1,208 @@
+package goladok3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCachingServer replies with an ETag, and 304 if If-None-Match matches it
+type mockCachingServer struct {
+	server *httptest.Server
+	mu     sync.Mutex
+	hits   int
+	// conditional counts requests with If-None-Match
+	conditional int
+	etag        string
+}
+
+func newMockCachingServer(t *testing.T) *mockCachingServer {
+	m := &mockCachingServer{etag: `"v1"`}
+	m.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.hits++
+		w.Header().Set("ETag", m.etag)
+		if match := r.Header.Get("If-None-Match"); match != "" {
+			m.conditional++
+			if match == m.etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+		w.Write(ladokmocks.JSONKataloginformationGrunddataLarosateinformation)
+	}))
+	return m
+}
+
+func (m *mockCachingServer) counts() (int, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hits, m.conditional
+}
+
+func TestCache(t *testing.T) {
+	tts := []struct {
+		name            string
+		cache           *CacheConfig
+		wantHits        int
+		wantConditional int
+	}{
+		{
+			name:     "disabled",
+			wantHits: 3,
+		},
+		{
+			name:            "revalidated",
+			cache:           &CacheConfig{},
+			wantHits:        3,
+			wantConditional: 2,
+		},
+		{
+			name:     "service ttl",
+			cache:    &CacheConfig{TTL: map[string]time.Duration{"kataloginformation": time.Hour}},
+			wantHits: 1,
+		},
+		{
+			name:            "other service ttl",
+			cache:           &CacheConfig{TTL: map[string]time.Duration{"studentinformation": time.Hour}},
+			wantHits:        3,
+			wantConditional: 2,
+		},
+		{
+			name:     "default ttl",
+			cache:    &CacheConfig{DefaultTTL: time.Hour},
+			wantHits: 1,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockCachingServer(t)
+			defer mock.server.Close()
+			client := mockNewClient(t, ladoktypes.EnvProdAPI, mock.server.URL, func(cfg *X509Config) {
+				cfg.Cache = tt.cache
+			})
+
+			var first *ladoktypes.KataloginformationGrunddataLarosatesinformation
+			for i := 0; i < 3; i++ {
+				got, resp, err := client.Kataloginformation.GetGrunddataLarosatesinformation(context.TODO())
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				if first == nil {
+					first = got
+				}
+				assert.Equal(t, first, got)
+			}
+
+			hits, conditional := mock.counts()
+			assert.Equal(t, tt.wantHits, hits)
+			assert.Equal(t, tt.wantConditional, conditional)
+		})
+	}
+}
+
+func TestCacheChanged(t *testing.T) {
+	mock := newMockCachingServer(t)
+	defer mock.server.Close()
+	backend := NewLRUCache(10)
+	client := mockNewClient(t, ladoktypes.EnvProdAPI, mock.server.URL, func(cfg *X509Config) {
+		cfg.Cache = &CacheConfig{Backend: backend}
+	})
+
+	_, _, err := client.Kataloginformation.GetGrunddataLarosatesinformation(context.TODO())
+	assert.NoError(t, err)
+
+	mock.mu.Lock()
+	mock.etag = `"v2"`
+	mock.mu.Unlock()
+
+	_, resp, err := client.Kataloginformation.GetGrunddataLarosatesinformation(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, `"v2"`, resp.Header.Get("ETag"))
+	assert.Equal(t, 1, backend.Len())
+
+	entry, ok := backend.Get(cacheKey(resp.Request))
+	if assert.True(t, ok) {
+		assert.Equal(t, `"v2"`, entry.Header.Get("ETag"))
+	}
+
+	// Accept header is part of the key
+	client.format = FormatXML
+	_, _, err = client.Kataloginformation.GetGrunddataLarosatesinformation(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, backend.Len())
+}
+
+func TestCacheWrite(t *testing.T) {
+	mock := newMockCachingServer(t)
+	defer mock.server.Close()
+	backend := NewLRUCache(10)
+	client := mockNewClient(t, ladoktypes.EnvProdAPI, mock.server.URL, func(cfg *X509Config) {
+		cfg.Cache = &CacheConfig{Backend: backend, DefaultTTL: time.Hour}
+	})
+
+	_, _, err := client.Kataloginformation.GetGrunddataLarosatesinformation(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, backend.Len())
+
+	_, err = client.Do(context.TODO(), "kataloginformation", http.MethodPut, "grunddata/larosatesinformation", []byte(`{}`), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, backend.Len())
+
+	_, _, err = client.Kataloginformation.GetGrunddataLarosatesinformation(context.TODO())
+	assert.NoError(t, err)
+	hits, _ := mock.counts()
+	assert.Equal(t, 3, hits)
+}
+
+func TestNotModifiedWithoutCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+	client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL)
+
+	got, resp, err := client.Kataloginformation.GetGrunddataLarosatesinformation(context.TODO())
+	assert.ErrorIs(t, err, ladoktypes.ErrNotModified)
+	assert.Nil(t, got)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", &CacheEntry{StatusCode: 1})
+	cache.Set("b", &CacheEntry{StatusCode: 2})
+
+	// a is used, so b is the least recently used
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+	cache.Set("c", &CacheEntry{StatusCode: 3})
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	a, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, a.StatusCode)
+
+	cache.Set("a", &CacheEntry{StatusCode: 4})
+	a, _ = cache.Get("a")
+	assert.Equal(t, 4, a.StatusCode)
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Delete("a")
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, cache.Len())
+}
@@ -0,0 +1,232 @@
+package goladok3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrNoInteraction if a replayed cassette has no interaction for the request
+	ErrNoInteraction = errors.New("No interaction found in cassette")
+)
+
+// scrubbedHeaders are never written to a cassette
+var scrubbedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+// DefaultScrubFields are the json keys and xml elements scrubbed from cassettes on top of Redact,
+// unless Cassette.ScrubFields is set
+var DefaultScrubFields = []string{"Fornamn", "Mellannamn", "Efternamn", "Fodelsedata", "Beslutsfattare"}
+
+// Cassette holds recorded ladok interactions, see Record and Replay
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+	// ScrubFields are the json keys and xml elements, like "Fornamn", whose values are scrubbed from recorded bodies
+	// on top of Redact, nil means DefaultScrubFields
+	ScrubFields []string `json:"-"`
+
+	mu sync.Mutex
+	// played counts the replays of each interaction
+	played map[*Interaction]int
+}
+
+// Interaction is a recorded request and its response, with personal data scrubbed by Redact and Cassette.ScrubFields
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is a recorded request, URL is the path and query without the ladok host
+type CassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// CassetteResponse is a recorded response
+type CassetteResponse struct {
+	StatusCode  int         `json:"status_code"`
+	Header      http.Header `json:"header,omitempty"`
+	ContentType string      `json:"content_type,omitempty"`
+	Body        string      `json:"body,omitempty"`
+}
+
+// LoadCassette reads a cassette saved by Save
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := &Cassette{}
+	if err := json.Unmarshal(b, cassette); err != nil {
+		return nil, fmt.Errorf("cassette %s: %w", path, err)
+	}
+	return cassette, nil
+}
+
+// Save writes the recorded interactions to path
+func (c *Cassette) Save(path string) error {
+	c.mu.Lock()
+	b, err := json.MarshalIndent(c, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0600)
+}
+
+// Record returns a Middleware adding every request and response made by the client to the cassette
+func (c *Cassette) Record() Middleware {
+	scrub := c.scrubber()
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			interaction := &Interaction{
+				Request: CassetteRequest{
+					Method: req.Method,
+					URL:    Redact(req.URL.RequestURI()),
+					Header: scrubHeader(req.Header),
+				},
+			}
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				b, err := io.ReadAll(body)
+				body.Close()
+				if err != nil {
+					return nil, err
+				}
+				interaction.Request.Body = scrub(string(b))
+			}
+
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				return resp, err
+			}
+
+			b, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(b))
+			if err != nil {
+				return resp, err
+			}
+
+			interaction.Response = CassetteResponse{
+				StatusCode:  resp.StatusCode,
+				Header:      scrubHeader(resp.Header),
+				ContentType: resp.Header.Get("Content-Type"),
+				Body:        scrub(string(b)),
+			}
+
+			c.mu.Lock()
+			c.Interactions = append(c.Interactions, interaction)
+			c.mu.Unlock()
+
+			return resp, nil
+		})
+	}
+}
+
+// Replay returns a Middleware answering requests from the cassette without reaching ladok.
+// Requests are matched by method and URL like mockGenericEndpointServer, ignoring the host.
+// Interactions with the same method and URL are replayed in recorded order, the last one is repeated.
+func (c *Cassette) Replay() Middleware {
+	return func(http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			interaction, err := c.match(req.Method, Redact(req.URL.RequestURI()))
+			if err != nil {
+				return nil, err
+			}
+
+			header := interaction.Response.Header.Clone()
+			if header == nil {
+				header = http.Header{}
+			}
+			if interaction.Response.ContentType != "" {
+				header.Set("Content-Type", interaction.Response.ContentType)
+			}
+
+			return &http.Response{
+				Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+				StatusCode:    interaction.Response.StatusCode,
+				Proto:         "HTTP/1.1",
+				ProtoMajor:    1,
+				ProtoMinor:    1,
+				Header:        header,
+				Body:          io.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+				ContentLength: int64(len(interaction.Response.Body)),
+				Request:       req,
+			}, nil
+		})
+	}
+}
+
+// match returns the next interaction recorded for method and url
+func (c *Cassette) match(method, url string) (*Interaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.played == nil {
+		c.played = map[*Interaction]int{}
+	}
+
+	var last *Interaction
+	for _, interaction := range c.Interactions {
+		if interaction.Request.Method != method || interaction.Request.URL != url {
+			continue
+		}
+		if c.played[interaction] == 0 {
+			c.played[interaction]++
+			return interaction, nil
+		}
+		last = interaction
+	}
+	if last == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, method, url)
+	}
+	c.played[last]++
+	return last, nil
+}
+
+// scrubber returns a function scrubbing a recorded body with Redact and the values of the scrubbed fields
+func (c *Cassette) scrubber() func(string) string {
+	fields := c.ScrubFields
+	if fields == nil {
+		fields = DefaultScrubFields
+	}
+	if len(fields) == 0 {
+		return Redact
+	}
+
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		quoted[i] = regexp.QuoteMeta(field)
+	}
+	names := strings.Join(quoted, "|")
+	jsonPattern := regexp.MustCompile(`("(?:` + names + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	xmlPattern := regexp.MustCompile(`(<(?:[A-Za-z0-9]+:)?(?:` + names + `)(?:\s[^>]*)?>)[^<]*(</)`)
+
+	return func(s string) string {
+		s = jsonPattern.ReplaceAllString(s, `$1"`+redacted+`"`)
+		s = xmlPattern.ReplaceAllString(s, `${1}`+redacted+`${2}`)
+		return Redact(s)
+	}
+}
+
+// scrubHeader returns a copy of header without credentials
+func scrubHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range scrubbedHeaders {
+		header.Del(name)
+	}
+	return header
+}
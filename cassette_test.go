@@ -0,0 +1,195 @@
+package goladok3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCassette(t *testing.T) {
+	personnummer := ladokmocks.Students[0].Personnummer
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mockGenericEndpointServer(t, mux, ContentTypeStudentinformationJSON, "GET", "/studentinformation/student/personnummer/"+personnummer, ladokmocks.JSONStudentinformationStudent, 200)
+	mockGenericEndpointServer(t, mux, ContentTypeKataloginformationJSON, "GET", "/kataloginformation/anvandare/autentiserad", ladokmocks.JSONKataloginformationAutentiserad, 200)
+	mockGenericEndpointServer(t, mux, ContentTypeAtomXML, "GET", "/handelser/feed/recent", ladokmocks.XMLFeedRecent, 200)
+	mockGenericEndpointServer(t, mux, ContentTypeKataloginformationJSON, "GET", "/kataloginformation/anvandarbehorighet/egna", ladokmocks.JSONErrors500, 500)
+
+	calls := func(client *Client) (*ladoktypes.Student, *ladoktypes.KataloginformationAnvandareAutentiserad, *ladoktypes.SuperFeed, error) {
+		student, _, err := client.Studentinformation.GetStudent(context.TODO(), &GetStudentReq{Personnummer: personnummer})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		autentiserad, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		feed, _, err := client.Feed.Recent(context.TODO())
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		_, _, err = client.Kataloginformation.GetAnvandarbehorighetEgna(context.TODO())
+		return student, autentiserad, feed, err
+	}
+
+	// Record
+	recording := &Cassette{}
+	recorder := mockNewClient(t, ladoktypes.EnvIntTestAPI, server.URL, func(cfg *X509Config) {
+		cfg.Middleware = []Middleware{recording.Record()}
+	})
+	wantStudent, wantAutentiserad, wantFeed, wantErr := calls(recorder)
+	assert.Len(t, recording.Interactions, 4)
+
+	path := filepath.Join(t.TempDir(), "ladok.json")
+	if !assert.NoError(t, recording.Save(path)) {
+		t.FailNow()
+	}
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), personnummer)
+	assert.NotContains(t, string(b), wantStudent.Personnummer)
+	assert.NotContains(t, string(b), wantStudent.Fornamn)
+	assert.NotContains(t, string(b), wantStudent.Efternamn)
+	assert.NotContains(t, string(b), wantStudent.Fodelsedata)
+
+	// Replay without network
+	cassette, err := LoadCassette(path)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	replayer := mockNewClient(t, ladoktypes.EnvIntTestAPI, "https://api.integrationstest.ladok.se", func(cfg *X509Config) {
+		cfg.Middleware = []Middleware{cassette.Replay()}
+	})
+	student, autentiserad, feed, err := calls(replayer)
+
+	// Personal data is scrubbed, everything else is replayed as recorded
+	assert.Equal(t, "[REDACTED]", student.Personnummer)
+	assert.Equal(t, wantStudent.UID, student.UID)
+	assert.Equal(t, "[REDACTED]", student.Fornamn)
+	assert.Equal(t, "[REDACTED]", student.Fodelsedata)
+	assert.Equal(t, "[REDACTED]", autentiserad.Anvandarnamn)
+	assert.Equal(t, wantAutentiserad.UID, autentiserad.UID)
+	assert.Equal(t, wantAutentiserad.Link, autentiserad.Link)
+	assert.Equal(t, wantFeed.ID, feed.ID)
+	assert.Len(t, feed.SuperEvents, len(wantFeed.SuperEvents))
+	assert.ErrorIs(t, wantErr, ladoktypes.ErrInternalServerError)
+	assert.ErrorIs(t, err, ladoktypes.ErrInternalServerError)
+	testLadokError(t, ladokmocks.Errors500, err)
+
+	// Not recorded
+	client := mockNewClient(t, ladoktypes.EnvIntTestAPI, "https://api.integrationstest.ladok.se", func(cfg *X509Config) {
+		cfg.Middleware = []Middleware{cassette.Replay()}
+	})
+	_, _, err = client.Kataloginformation.GetGrunddataLarosatesinformation(context.TODO())
+	assert.ErrorIs(t, err, ErrNoInteraction)
+}
+
+func TestCassetteScrubFields(t *testing.T) {
+	tts := []struct {
+		name        string
+		scrubFields []string
+		have        string
+		want        string
+	}{
+		{
+			name: "default json",
+			have: `{"Fornamn": "Anna", "Efternamn": "Andersson", "Fodelsedata": "1996-11-05", "Uid": "1"}`,
+			want: `{"Fornamn": "[REDACTED]", "Efternamn": "[REDACTED]", "Fodelsedata": "[REDACTED]", "Uid": "1"}`,
+		},
+		{
+			name: "default xml",
+			have: `<si:Student><si:Fornamn>Anna</si:Fornamn><si:Fodelsedata>1996-11-05</si:Fodelsedata><base:Uid>1</base:Uid></si:Student>`,
+			want: `<si:Student><si:Fornamn>[REDACTED]</si:Fornamn><si:Fodelsedata>[REDACTED]</si:Fodelsedata><base:Uid>1</base:Uid></si:Student>`,
+		},
+		{
+			name:        "configured",
+			scrubFields: []string{"Kommentar"},
+			have:        `{"Fornamn": "Anna", "Kommentar": "Anna Andersson"}`,
+			want:        `{"Fornamn": "Anna", "Kommentar": "[REDACTED]"}`,
+		},
+		{
+			name:        "none",
+			scrubFields: []string{},
+			have:        `{"Fornamn": "Anna"}`,
+			want:        `{"Fornamn": "Anna"}`,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			cassette := &Cassette{ScrubFields: tt.scrubFields}
+			assert.Equal(t, tt.want, cassette.scrubber()(tt.have))
+		})
+	}
+}
+
+func TestCassetteReplayOrder(t *testing.T) {
+	cassette := &Cassette{Interactions: []*Interaction{
+		{Request: CassetteRequest{Method: "GET", URL: "/test"}, Response: CassetteResponse{StatusCode: 503}},
+		{Request: CassetteRequest{Method: "GET", URL: "/test"}, Response: CassetteResponse{StatusCode: 200}},
+		{Request: CassetteRequest{Method: "POST", URL: "/test"}, Response: CassetteResponse{StatusCode: 201}},
+	}}
+
+	for _, want := range []int{503, 200, 200} {
+		interaction, err := cassette.match("GET", "/test")
+		if assert.NoError(t, err) {
+			assert.Equal(t, want, interaction.Response.StatusCode)
+		}
+	}
+	interaction, err := cassette.match("POST", "/test")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 201, interaction.Response.StatusCode)
+	}
+	_, err = cassette.match("DELETE", "/test")
+	assert.ErrorIs(t, err, ErrNoInteraction)
+}
+
+func TestCassetteNumbers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeStudentinformationJSON)
+		w.Write([]byte(`{"Id": 1650000000, "LarosateID": 199611052383, "Personnummer": "199611052383"}`))
+	}))
+	defer server.Close()
+
+	type reply struct {
+		ID           int    `json:"Id"`
+		LarosateID   int    `json:"LarosateID"`
+		Personnummer string `json:"Personnummer"`
+	}
+
+	recording := &Cassette{}
+	want := &reply{}
+	recorder := mockNewClient(t, ladoktypes.EnvIntTestAPI, server.URL, func(cfg *X509Config) {
+		cfg.Middleware = []Middleware{recording.Record()}
+	})
+	_, err := recorder.Do(context.TODO(), "studentinformation", "GET", "student/1", nil, want)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	assert.NoError(t, recording.Save(path))
+	cassette, err := LoadCassette(path)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	got := &reply{}
+	replayer := mockNewClient(t, ladoktypes.EnvIntTestAPI, server.URL, func(cfg *X509Config) {
+		cfg.Middleware = []Middleware{cassette.Replay()}
+	})
+	_, err = replayer.Do(context.TODO(), "studentinformation", "GET", "student/1", nil, got)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, 1650000000, got.ID)
+	assert.Equal(t, want.LarosateID, got.LarosateID)
+	assert.Equal(t, redacted, got.Personnummer)
+}
@@ -0,0 +1,252 @@
+package goladok3
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CertificateLoader returns a PEM encoded client certificate and private key
+type CertificateLoader func() (certificatePEM, privateKeyPEM []byte, err error)
+
+// FileCertificateLoader returns a CertificateLoader reading PEM files from disk
+func FileCertificateLoader(certificatePath, privateKeyPath string) CertificateLoader {
+	return func() ([]byte, []byte, error) {
+		certificatePEM, err := os.ReadFile(certificatePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		privateKeyPEM, err := os.ReadFile(privateKeyPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return certificatePEM, privateKeyPEM, nil
+	}
+}
+
+// CertificateInfo describes the client certificate in use
+type CertificateInfo struct {
+	Subject string
+	// OrganizationalUnits holds all OUs of the subject, one of them names the ladok environment
+	OrganizationalUnits []string
+	// Environment is the ladok environment found among the OUs, empty if none
+	Environment string
+	// Larosate is the lärosäte code after the @ in the subject common name, like KF in sunet@KF
+	Larosate     string
+	SerialNumber *big.Int
+	NotBefore    time.Time
+	NotAfter     time.Time
+	// DaysRemaining is the number of started days left until NotAfter, 0 or less when expired
+	DaysRemaining int
+	// LoadedAt is when the certificate was loaded into the client
+	LoadedAt time.Time
+}
+
+// certificateStore holds the active client certificate and swaps it on reload
+type certificateStore struct {
+	mu             sync.RWMutex
+	keyPair        *tls.Certificate
+	certificate    *x509.Certificate
+	certificatePEM []byte
+	privateKeyPEM  []byte
+	loadedAt       time.Time
+
+	loader CertificateLoader
+	// env is the ladok environment of the first certificate
+	env string
+	// onSwap is called after a new certificate is in use
+	onSwap func()
+}
+
+// newCertificateStore parses and validates the first certificate
+func newCertificateStore(certificatePEM, privateKeyPEM []byte, loader CertificateLoader) (*certificateStore, error) {
+	s := &certificateStore{loader: loader}
+
+	if loader != nil {
+		var err error
+		certificatePEM, privateKeyPEM, err = loader()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.swap(certificatePEM, privateKeyPEM); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// swap validates the new pair and makes it the active one, it reports if anything changed
+func (s *certificateStore) swap(certificatePEM, privateKeyPEM []byte) (bool, error) {
+	s.mu.RLock()
+	unchanged := bytes.Equal(certificatePEM, s.certificatePEM) && bytes.Equal(privateKeyPEM, s.privateKeyPEM)
+	s.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	keyPair, err := tls.X509KeyPair(certificatePEM, privateKeyPEM)
+	if err != nil {
+		return false, err
+	}
+	certificate, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return false, err
+	}
+	keyPair.Leaf = certificate
+
+	// A certificate without ladok environment is accepted at start, but a reloaded certificate has to keep the environment
+	env, _ := certificateEnvironment(certificate)
+
+	s.mu.Lock()
+	if s.certificate != nil {
+		if time.Now().After(certificate.NotAfter) {
+			s.mu.Unlock()
+			return false, fmt.Errorf("certificate %s expired %s", certificate.SerialNumber, certificate.NotAfter)
+		}
+		if env != s.env {
+			s.mu.Unlock()
+			return false, fmt.Errorf("certificate environment %q does not match %q", env, s.env)
+		}
+	}
+	s.env = env
+	s.keyPair = &keyPair
+	s.certificate = certificate
+	s.certificatePEM = certificatePEM
+	s.privateKeyPEM = privateKeyPEM
+	s.loadedAt = time.Now()
+	onSwap := s.onSwap
+	s.mu.Unlock()
+
+	if onSwap != nil {
+		onSwap()
+	}
+	return true, nil
+}
+
+// reload calls the loader and swaps in the new certificate if it has changed
+func (s *certificateStore) reload() (bool, error) {
+	if s.loader == nil {
+		return false, nil
+	}
+	certificatePEM, privateKeyPEM, err := s.loader()
+	if err != nil {
+		return false, err
+	}
+	return s.swap(certificatePEM, privateKeyPEM)
+}
+
+// watch reloads every interval until done is closed
+func (s *certificateStore) watch(interval time.Duration, done <-chan struct{}, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if _, err := s.reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// getClientCertificate is used as tls.Config.GetClientCertificate
+func (s *certificateStore) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keyPair, nil
+}
+
+// current returns the active certificate
+func (s *certificateStore) current() *x509.Certificate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.certificate
+}
+
+func (s *certificateStore) info() CertificateInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	env, _ := certificateEnvironment(s.certificate)
+
+	var larosate string
+	if i := strings.LastIndex(s.certificate.Subject.CommonName, "@"); i >= 0 {
+		larosate = s.certificate.Subject.CommonName[i+1:]
+	}
+
+	return CertificateInfo{
+		Subject:             s.certificate.Subject.String(),
+		OrganizationalUnits: s.certificate.Subject.OrganizationalUnit,
+		Environment:         env,
+		Larosate:            larosate,
+		SerialNumber:        s.certificate.SerialNumber,
+		NotBefore:           s.certificate.NotBefore,
+		NotAfter:            s.certificate.NotAfter,
+		DaysRemaining:       int(math.Ceil(time.Until(s.certificate.NotAfter).Hours() / 24)),
+		LoadedAt:            s.loadedAt,
+	}
+}
+
+// monitorExpiry calls onWarning at start and then every interval, as long as the certificate
+// in use has warningDays or less remaining, until done is closed
+func (s *certificateStore) monitorExpiry(warningDays int, interval time.Duration, done <-chan struct{}, onWarning func(CertificateInfo)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if info := s.info(); info.DaysRemaining <= warningDays {
+			onWarning(info)
+		}
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReloadCertificate loads the client certificate again from X509Config.CertificateLoader.
+// The new certificate is only used if it is valid and from the same ladok environment.
+func (c *Client) ReloadCertificate() error {
+	if c.certificates == nil {
+		return nil
+	}
+	_, err := c.certificates.reload()
+	return err
+}
+
+// CertificateInfo returns information about the client certificate in use, zero for OIDC clients
+func (c *Client) CertificateInfo() CertificateInfo {
+	if c.certificates == nil {
+		return CertificateInfo{}
+	}
+	return c.certificates.info()
+}
+
+// Certificate returns the client certificate in use, nil for OIDC clients
+func (c *Client) Certificate() *x509.Certificate {
+	if c.certificates == nil {
+		return nil
+	}
+	return c.certificates.current()
+}
+
+// Close stops background work like certificate watching, Close of a clone leaves the work of its client running
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	return nil
+}
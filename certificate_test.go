@@ -0,0 +1,365 @@
+package goladok3
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCertificateFiles writes a new mock certificate and key to dir
+func mockCertificateFiles(t *testing.T, dir, env string, notAfter int) (string, string, *x509.Certificate) {
+	certPEM, cert, keyPEM, _ := ladokmocks.MockCertificateAndKey(t, env, 0, notAfter)
+
+	certPath := filepath.Join(dir, "ladok.crt")
+	keyPath := filepath.Join(dir, "ladok.key")
+	if !assert.NoError(t, os.WriteFile(certPath, certPEM, 0600)) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, os.WriteFile(keyPath, keyPEM, 0600)) {
+		t.FailNow()
+	}
+	return certPath, keyPath, cert
+}
+
+func TestReloadCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath, first := mockCertificateFiles(t, dir, ladoktypes.EnvIntTestAPI, 100)
+
+	client, err := NewX509(X509Config{
+		URL:               "https://api.integrationstest.ladok.se",
+		CertificateLoader: FileCertificateLoader(certPath, keyPath),
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer client.Close()
+
+	info := client.CertificateInfo()
+	assert.Equal(t, first.NotAfter, info.NotAfter)
+	assert.Equal(t, first.SerialNumber, info.SerialNumber)
+
+	// Unchanged files
+	assert.NoError(t, client.ReloadCertificate())
+	assert.Equal(t, info, client.CertificateInfo())
+
+	// New certificate for the same environment
+	_, _, second := mockCertificateFiles(t, dir, ladoktypes.EnvIntTestAPI, 465)
+	assert.NoError(t, client.ReloadCertificate())
+	assert.Equal(t, second.NotAfter, client.CertificateInfo().NotAfter)
+
+	// Certificate for another environment is rejected
+	mockCertificateFiles(t, dir, ladoktypes.EnvProdAPI, 465)
+	assert.Error(t, client.ReloadCertificate())
+	assert.Equal(t, second.NotAfter, client.CertificateInfo().NotAfter)
+
+	// Expired certificate is rejected
+	certPEM, _, keyPEM, _ := ladokmocks.MockCertificateAndKey(t, ladoktypes.EnvIntTestAPI, -10, -1)
+	assert.NoError(t, os.WriteFile(certPath, certPEM, 0600))
+	assert.NoError(t, os.WriteFile(keyPath, keyPEM, 0600))
+	assert.Error(t, client.ReloadCertificate())
+
+	// Broken key is rejected
+	assert.NoError(t, os.WriteFile(keyPath, []byte("broken"), 0600))
+	assert.Error(t, client.ReloadCertificate())
+	assert.Equal(t, second.NotAfter, client.CertificateInfo().NotAfter)
+
+	env, err := client.Environment()
+	assert.NoError(t, err)
+	assert.Equal(t, ladoktypes.EnvIntTestAPI, env)
+}
+
+func TestWatchCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath, _ := mockCertificateFiles(t, dir, ladoktypes.EnvProdAPI, 100)
+
+	var (
+		mu        sync.Mutex
+		reloadErr error
+	)
+	client, err := NewX509(X509Config{
+		URL:                       "https://api.ladok.se",
+		CertificateLoader:         FileCertificateLoader(certPath, keyPath),
+		CertificateReloadInterval: 10 * time.Millisecond,
+		OnCertificateReloadError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			reloadErr = err
+		},
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer client.Close()
+
+	_, _, second := mockCertificateFiles(t, dir, ladoktypes.EnvProdAPI, 200)
+	assert.Eventually(t, func() bool {
+		return client.CertificateInfo().NotAfter.Equal(second.NotAfter)
+	}, time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, os.Remove(keyPath))
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return errors.Is(reloadErr, os.ErrNotExist)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestReloadCertificateTLS(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen []time.Time
+	)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.TLS.PeerCertificates[0].NotAfter)
+		mu.Unlock()
+		w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+		w.Write(ladokmocks.JSONKataloginformationAutentiserad)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	certPath, keyPath, first := mockCertificateFiles(t, dir, ladoktypes.EnvProdAPI, 100)
+
+	client, err := NewX509(X509Config{
+		URL:               server.URL,
+		CertificateLoader: FileCertificateLoader(certPath, keyPath),
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer client.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	client.HTTPClient.Transport.(*http.Transport).TLSClientConfig.RootCAs = roots
+
+	_, _, err = client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.NoError(t, err)
+
+	_, _, second := mockCertificateFiles(t, dir, ladoktypes.EnvProdAPI, 200)
+	assert.NoError(t, client.ReloadCertificate())
+
+	_, _, err = client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if assert.Len(t, seen, 2) {
+		assert.True(t, first.NotAfter.Equal(seen[0]))
+		assert.True(t, second.NotAfter.Equal(seen[1]))
+	}
+}
+
+func TestCertificateInfo(t *testing.T) {
+	tts := []struct {
+		name              string
+		env               string
+		notBefore         int
+		notAfter          int
+		wantDaysRemaining int
+	}{
+		{
+			name:              "valid",
+			env:               ladoktypes.EnvProdAPI,
+			notBefore:         0,
+			notAfter:          100,
+			wantDaysRemaining: 100,
+		},
+		{
+			name:              "expires soon",
+			env:               ladoktypes.EnvTestAPI,
+			notBefore:         -300,
+			notAfter:          5,
+			wantDaysRemaining: 5,
+		},
+		{
+			name:              "expired",
+			env:               ladoktypes.EnvIntTestAPI,
+			notBefore:         -10,
+			notAfter:          -1,
+			wantDaysRemaining: -1,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			certPEM, cert, keyPEM, _ := ladokmocks.MockCertificateAndKey(t, tt.env, tt.notBefore, tt.notAfter)
+			client, err := NewX509(X509Config{
+				CertificatePEM: certPEM,
+				PrivateKeyPEM:  keyPEM,
+			})
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			info := client.CertificateInfo()
+			assert.Equal(t, cert.Subject.String(), info.Subject)
+			assert.Equal(t, []string{"LED", tt.env}, info.OrganizationalUnits)
+			assert.Equal(t, tt.env, info.Environment)
+			assert.Equal(t, "KF", info.Larosate)
+			assert.Equal(t, cert.SerialNumber, info.SerialNumber)
+			assert.Equal(t, cert.NotBefore, info.NotBefore)
+			assert.Equal(t, cert.NotAfter, info.NotAfter)
+			assert.Equal(t, tt.wantDaysRemaining, info.DaysRemaining)
+		})
+	}
+}
+
+func TestCertificateExpiryWarning(t *testing.T) {
+	tts := []struct {
+		name        string
+		notAfter    int
+		warningDays int
+		wantWarning bool
+	}{
+		{
+			name:        "within warning days",
+			notAfter:    5,
+			warningDays: 30,
+			wantWarning: true,
+		},
+		{
+			name:        "on the warning day",
+			notAfter:    30,
+			warningDays: 30,
+			wantWarning: true,
+		},
+		{
+			name:        "outside warning days",
+			notAfter:    100,
+			warningDays: 30,
+			wantWarning: false,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			certPEM, _, keyPEM, _ := ladokmocks.MockCertificateAndKey(t, ladoktypes.EnvProdAPI, 0, tt.notAfter)
+
+			warnings := make(chan CertificateInfo, 10)
+			client, err := NewX509(X509Config{
+				CertificatePEM:                 certPEM,
+				PrivateKeyPEM:                  keyPEM,
+				CertificateExpiryWarningDays:   tt.warningDays,
+				CertificateExpiryCheckInterval: 10 * time.Millisecond,
+				OnCertificateExpiryWarning: func(info CertificateInfo) {
+					select {
+					case warnings <- info:
+					default:
+					}
+				},
+			})
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer client.Close()
+
+			select {
+			case info := <-warnings:
+				assert.True(t, tt.wantWarning, "unexpected warning")
+				assert.Equal(t, tt.notAfter, info.DaysRemaining)
+				// Warnings are repeated every check interval
+				select {
+				case <-warnings:
+				case <-time.After(time.Second):
+					t.Error("warning not repeated")
+				}
+			case <-time.After(100 * time.Millisecond):
+				assert.False(t, tt.wantWarning, "no warning")
+			}
+		})
+	}
+}
+
+func TestCertificateExpiryWarningAfterCloneClose(t *testing.T) {
+	certPEM, _, keyPEM, _ := ladokmocks.MockCertificateAndKey(t, ladoktypes.EnvProdAPI, 0, 10)
+
+	warnings := make(chan CertificateInfo, 10)
+	client, err := NewX509(X509Config{
+		CertificatePEM:                 certPEM,
+		PrivateKeyPEM:                  keyPEM,
+		CertificateExpiryWarningDays:   30,
+		CertificateExpiryCheckInterval: 10 * time.Millisecond,
+		OnCertificateExpiryWarning: func(info CertificateInfo) {
+			select {
+			case warnings <- info:
+			default:
+			}
+		},
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer client.Close()
+
+	clone, err := client.Clone()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, clone.Close())
+
+	// Drain warnings sent before the clone was closed
+	time.Sleep(50 * time.Millisecond)
+	for len(warnings) > 0 {
+		<-warnings
+	}
+	select {
+	case <-warnings:
+	case <-time.After(time.Second):
+		t.Error("no warning after the clone was closed")
+	}
+}
+
+func TestCertificateIntervals(t *testing.T) {
+	tts := []struct {
+		name    string
+		config  X509Config
+		wantErr bool
+	}{
+		{
+			name:   "default expiry check interval",
+			config: X509Config{OnCertificateExpiryWarning: func(CertificateInfo) {}},
+		},
+		{
+			name:    "negative expiry check interval",
+			config:  X509Config{OnCertificateExpiryWarning: func(CertificateInfo) {}, CertificateExpiryCheckInterval: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "negative reload interval",
+			config:  X509Config{CertificateReloadInterval: -time.Second},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			certPEM, _, keyPEM, _ := ladokmocks.MockCertificateAndKey(t, ladoktypes.EnvProdAPI, 0, 100)
+			tt.config.CertificatePEM = certPEM
+			tt.config.PrivateKeyPEM = keyPEM
+
+			client, err := NewX509(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			client.Close()
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package goladok3
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+var (
+	// ErrNoPrivateKeyInPEM if the private key PEM holds no private key block
+	ErrNoPrivateKeyInPEM = errors.New("No private key found in PEM")
+	// ErrNoCertificateInPEM if the certificate PEM holds no certificate block
+	ErrNoCertificateInPEM = errors.New("No certificate found in PEM")
+	// ErrLegacyEncryptedPEM if the private key is encrypted with the insecure "Proc-Type: 4,ENCRYPTED" PEM encryption,
+	// convert it with "openssl pkcs8 -topk8" to an encrypted PKCS#8 key
+	ErrLegacyEncryptedPEM = errors.New("Legacy encrypted PEM private keys are not supported, use encrypted PKCS#8")
+)
+
+// NewX509FromPKCS12 creates a new x509 instance of ladok from a PKCS#12 (.p12) bundle as issued by ladok,
+// the intermediate certificates of the bundle are sent along with the client certificate.
+// CertificatePEM and PrivateKeyPEM of config are set from the bundle.
+func NewX509FromPKCS12(config X509Config, pkcs12Data []byte, password string) (*Client, error) {
+	certificatePEM, privateKeyPEM, err := decodePKCS12(pkcs12Data, password)
+	if err != nil {
+		return nil, err
+	}
+	config.CertificatePEM = certificatePEM
+	config.PrivateKeyPEM = privateKeyPEM
+
+	return NewX509(config)
+}
+
+// NewX509FromFiles creates a new x509 instance of ladok from PEM files on disk, the private key may be
+// an encrypted PKCS#8 key protected by passphrase. The files are read by config.CertificateLoader, so ReloadCertificate and
+// CertificateReloadInterval pick up new files.
+func NewX509FromFiles(config X509Config, certificatePath, privateKeyPath, passphrase string) (*Client, error) {
+	config.CertificateLoader = EncryptedFileCertificateLoader(certificatePath, privateKeyPath, passphrase)
+
+	return NewX509(config)
+}
+
+// EncryptedFileCertificateLoader returns a CertificateLoader reading PEM files from disk,
+// the private key is decrypted with passphrase when encrypted
+func EncryptedFileCertificateLoader(certificatePath, privateKeyPath, passphrase string) CertificateLoader {
+	load := FileCertificateLoader(certificatePath, privateKeyPath)
+	return func() ([]byte, []byte, error) {
+		certificatePEM, privateKeyPEM, err := load()
+		if err != nil {
+			return nil, nil, err
+		}
+		privateKeyPEM, err = decryptPrivateKeyPEM(privateKeyPEM, passphrase)
+		if err != nil {
+			return nil, nil, err
+		}
+		return certificatePEM, privateKeyPEM, nil
+	}
+}
+
+// PKCS12FileCertificateLoader returns a CertificateLoader reading a PKCS#12 bundle from disk
+func PKCS12FileCertificateLoader(path, password string) CertificateLoader {
+	return func() ([]byte, []byte, error) {
+		pkcs12Data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return decodePKCS12(pkcs12Data, password)
+	}
+}
+
+// decodePKCS12 returns the client certificate followed by its intermediates, and the private key, as PEM
+func decodePKCS12(pkcs12Data []byte, password string) ([]byte, []byte, error) {
+	privateKey, certificate, intermediates, err := pkcs12.DecodeChain(pkcs12Data, password)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode pkcs12: %w", err)
+	}
+
+	privateKeyPEM, err := marshalPrivateKeyPEM(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certificatePEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})
+	for _, intermediate := range intermediates {
+		certificatePEM = append(certificatePEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: intermediate.Raw})...)
+	}
+
+	return certificatePEM, privateKeyPEM, nil
+}
+
+// decryptPrivateKeyPEM returns an unencrypted PKCS#8 PEM of an encrypted PKCS#8 PEM,
+// an unencrypted privateKeyPEM is returned as is
+func decryptPrivateKeyPEM(privateKeyPEM []byte, passphrase string) ([]byte, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, ErrNoPrivateKeyInPEM
+	}
+
+	switch {
+	case block.Type == "ENCRYPTED PRIVATE KEY":
+		privateKey, err := pkcs8.ParsePKCS8PrivateKey(block.Bytes, []byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("decrypt private key: %w", err)
+		}
+		return marshalPrivateKeyPEM(privateKey)
+
+	// Legacy "Proc-Type: 4,ENCRYPTED" keys, as written by older openssl versions, are open to padding oracle attacks
+	case block.Headers["Proc-Type"] == "4,ENCRYPTED":
+		return nil, ErrLegacyEncryptedPEM
+	}
+
+	return privateKeyPEM, nil
+}
+
+// marshalPrivateKeyPEM returns privateKey as PKCS#8 PEM, RSA, ECDSA and Ed25519 keys are supported
+func marshalPrivateKeyPEM(privateKey interface{}) ([]byte, error) {
+	switch privateKey.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, privateKey)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// parseCertificatePEM returns the first certificate of certificatePEM
+func parseCertificatePEM(certificatePEM []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, certificatePEM = pem.Decode(certificatePEM)
+		if block == nil {
+			return nil, ErrNoCertificateInPEM
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
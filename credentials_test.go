@@ -0,0 +1,172 @@
+package goladok3
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func mockPrivateKey(t *testing.T, keyType string) crypto.Signer {
+	var (
+		privateKey crypto.Signer
+		err        error
+	)
+	switch keyType {
+	case "rsa":
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "ecdsa":
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return privateKey
+}
+
+func TestNewX509FromPKCS12(t *testing.T) {
+	tts := []struct {
+		name     string
+		keyType  string
+		password string
+		wantErr  bool
+	}{
+		{
+			name:     "rsa",
+			keyType:  "rsa",
+			password: "s3cret",
+		},
+		{
+			name:     "ecdsa",
+			keyType:  "ecdsa",
+			password: "s3cret",
+		},
+		{
+			name:     "wrong password",
+			keyType:  "rsa",
+			password: "wrong",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey := mockPrivateKey(t, tt.keyType)
+			cert, intermediate := ladokmocks.MockCertificateChain(t, ladoktypes.EnvIntTestAPI, privateKey, 0, 100)
+
+			pkcs12Data, err := pkcs12.Modern.Encode(privateKey, cert, []*x509.Certificate{intermediate}, "s3cret")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			client, err := NewX509FromPKCS12(X509Config{URL: "https://api.integrationstest.ladok.se"}, pkcs12Data, tt.password)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			assert.Equal(t, cert.Raw, client.Certificate().Raw)
+			assert.Equal(t, [][]byte{cert.Raw, intermediate.Raw}, client.certificates.keyPair.Certificate)
+			assert.Equal(t, privateKey.Public(), client.certificates.keyPair.PrivateKey.(crypto.Signer).Public())
+		})
+	}
+}
+
+func TestNewX509FromFiles(t *testing.T) {
+	tts := []struct {
+		name       string
+		keyType    string
+		encrypt    func(t *testing.T, privateKey crypto.Signer) []byte
+		passphrase string
+		wantErr    bool
+	}{
+		{
+			name:    "rsa pkcs1",
+			keyType: "rsa",
+			encrypt: func(t *testing.T, privateKey crypto.Signer) []byte {
+				return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey.(*rsa.PrivateKey))})
+			},
+		},
+		{
+			name:       "rsa encrypted pkcs8",
+			keyType:    "rsa",
+			encrypt:    mockEncryptedPKCS8,
+			passphrase: "s3cret",
+		},
+		{
+			name:       "ecdsa encrypted pkcs8",
+			keyType:    "ecdsa",
+			encrypt:    mockEncryptedPKCS8,
+			passphrase: "s3cret",
+		},
+		{
+			name:       "wrong passphrase",
+			keyType:    "ecdsa",
+			encrypt:    mockEncryptedPKCS8,
+			passphrase: "wrong",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey := mockPrivateKey(t, tt.keyType)
+			cert, intermediate := ladokmocks.MockCertificateChain(t, ladoktypes.EnvIntTestAPI, privateKey, 0, 100)
+
+			dir := t.TempDir()
+			certPath := filepath.Join(dir, "ladok.crt")
+			keyPath := filepath.Join(dir, "ladok.key")
+			certPEM := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: intermediate.Raw})...)
+			assert.NoError(t, os.WriteFile(certPath, certPEM, 0600))
+			assert.NoError(t, os.WriteFile(keyPath, tt.encrypt(t, privateKey), 0600))
+
+			client, err := NewX509FromFiles(X509Config{URL: "https://api.integrationstest.ladok.se"}, certPath, keyPath, tt.passphrase)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			defer client.Close()
+
+			assert.Equal(t, cert.Raw, client.Certificate().Raw)
+			assert.Len(t, client.certificates.keyPair.Certificate, 2)
+			assert.NoError(t, client.ReloadCertificate())
+		})
+	}
+}
+
+func mockEncryptedPKCS8(t *testing.T, privateKey crypto.Signer) []byte {
+	der, err := pkcs8.MarshalPrivateKey(privateKey, []byte("s3cret"), nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der})
+}
+
+func TestLegacyEncryptedPEM(t *testing.T) {
+	privateKey := mockPrivateKey(t, "rsa")
+	block, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey.(*rsa.PrivateKey)), []byte("s3cret"), x509.PEMCipherAES256)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_, err = decryptPrivateKeyPEM(pem.EncodeToMemory(block), "s3cret")
+	assert.ErrorIs(t, err, ErrLegacyEncryptedPEM)
+}
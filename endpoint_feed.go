@@ -7,6 +7,8 @@ import (
 	"strconv"
 
 	"github.com/masv3971/goladok3/ladoktypes"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type feedService struct {
@@ -15,11 +17,11 @@ type feedService struct {
 }
 
 func (s *feedService) acceptHeader() string {
-	return ladokAcceptHeader[s.service]["xml"]
+	return ladokAcceptHeader[s.service][FormatXML]
 }
 
 func (s *feedService) feedURL(ctx context.Context) (string, error) {
-	env, err := s.client.environment(ctx)
+	env, err := s.client.Environment()
 	if err != nil {
 		return "", err
 	}
@@ -32,7 +34,7 @@ func (s *feedService) feedURL(ctx context.Context) (string, error) {
 	}
 }
 
-func (s *feedService) atomReader(ctx context.Context, param string) (*ladoktypes.SuperFeed, *http.Response, error) {
+func (s *feedService) atomReader(ctx context.Context, operation, param string) (*ladoktypes.SuperFeed, *http.Response, error) {
 	envURL, err := s.feedURL(ctx)
 	if err != nil {
 		return nil, nil, err
@@ -41,15 +43,25 @@ func (s *feedService) atomReader(ctx context.Context, param string) (*ladoktypes
 	url := fmt.Sprintf("%s/%s", envURL, param)
 
 	reply := &ladoktypes.Feed{}
-	resp, err := s.client.call(ctx, s.acceptHeader(), http.MethodGet, url, nil, reply)
+	resp, err := s.client.call(ctx, Endpoint{Service: s.service, Operation: operation}, s.acceptHeader(), http.MethodGet, url, nil, reply)
 	if err != nil {
 		return nil, resp, err
 	}
 
+	_, span := s.client.tracer.Start(ctx, s.service+".Parse", trace.WithAttributes(
+		attributeService.String(s.service),
+		attributeOperation.String(operation),
+	))
 	superFeed, err := reply.Parse()
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		s.client.metrics.IncDecodeFailures(s.service, operation)
 		return nil, resp, err
 	}
+	span.SetAttributes(attributeFeedID.Int(superFeed.ID), attributeFeedEvents.Int(len(superFeed.SuperEvents)))
+	span.End()
+	s.client.metrics.ObserveFeed(operation, superFeed.ID)
 
 	return superFeed, resp, nil
 
@@ -57,7 +69,7 @@ func (s *feedService) atomReader(ctx context.Context, param string) (*ladoktypes
 
 // Recent atom feed .../feed/recent gets the most recent publicized feed
 func (s *feedService) Recent(ctx context.Context) (*ladoktypes.SuperFeed, *http.Response, error) {
-	superFeed, resp, err := s.atomReader(ctx, "recent")
+	superFeed, resp, err := s.atomReader(ctx, "Recent", "recent")
 	if err != nil {
 		return nil, resp, err
 	}
@@ -76,7 +88,7 @@ func (s *feedService) Historical(ctx context.Context, req *HistoricalReq) (*lado
 		return nil, nil, err
 	}
 
-	superFeed, resp, err := s.atomReader(ctx, strconv.Itoa(req.ID))
+	superFeed, resp, err := s.atomReader(ctx, "Historical", strconv.Itoa(req.ID))
 	if err != nil {
 		return nil, resp, err
 	}
@@ -86,7 +98,7 @@ func (s *feedService) Historical(ctx context.Context, req *HistoricalReq) (*lado
 
 // First atom feed .../feed/first gets the first publicized feed
 func (s *feedService) First(ctx context.Context) (*ladoktypes.SuperFeed, *http.Response, error) {
-	superFeed, resp, err := s.atomReader(ctx, "first")
+	superFeed, resp, err := s.atomReader(ctx, "First", "first")
 	if err != nil {
 		return nil, resp, err
 	}
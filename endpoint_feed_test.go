@@ -127,7 +127,7 @@ func testFeed(t *testing.T, env string, url string) {
 					assert.Equal(t, tt.clientReplyType, got, "Should be equal")
 				case 500:
 					_, _, err = fn(context.TODO())
-					assert.Equal(t, tt.clientReplyType.(*ladoktypes.LadokError), err)
+					testLadokError(t, tt.clientReplyType.(*ladoktypes.LadokError), err)
 				}
 				// Historical
 			case func(context.Context, *HistoricalReq) (*ladoktypes.SuperFeed, *http.Response, error):
@@ -138,7 +138,7 @@ func testFeed(t *testing.T, env string, url string) {
 					assert.Equal(t, tt.clientReplyType, got, "Should be equal")
 				case 500:
 					_, _, err = fn(context.TODO(), tt.clientReq.(*HistoricalReq))
-					assert.Equal(t, tt.clientReplyType.(*ladoktypes.LadokError), err)
+					testLadokError(t, tt.clientReplyType.(*ladoktypes.LadokError), err)
 				}
 			}
 
@@ -22,7 +22,7 @@ func (s *kataloginformationService) acceptHeader() string {
 func (s *kataloginformationService) GetAnvandareAutentiserad(ctx context.Context) (*ladoktypes.KataloginformationAnvandareAutentiserad, *http.Response, error) {
 	url := fmt.Sprintf("%s/%s", s.service, "anvandare/autentiserad")
 	reply := &ladoktypes.KataloginformationAnvandareAutentiserad{}
-	resp, err := s.client.call(ctx, s.acceptHeader(), http.MethodGet, url, nil, reply)
+	resp, err := s.client.call(ctx, Endpoint{Service: s.service, Operation: "GetAnvandareAutentiserad"}, s.acceptHeader(), http.MethodGet, url, nil, reply)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -42,7 +42,7 @@ func (s *kataloginformationService) GetBehorighetsprofil(ctx context.Context, re
 
 	url := fmt.Sprintf("%s/%s/%s", s.service, "behorighetsprofil", req.UID)
 	reply := &ladoktypes.KataloginformationBehorighetsprofil{}
-	resp, err := s.client.call(ctx, s.acceptHeader(), http.MethodGet, url, nil, reply)
+	resp, err := s.client.call(ctx, Endpoint{Service: s.service, Operation: "GetBehorighetsprofil"}, s.acceptHeader(), http.MethodGet, url, nil, reply)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -53,7 +53,7 @@ func (s *kataloginformationService) GetBehorighetsprofil(ctx context.Context, re
 func (s *kataloginformationService) GetAnvandarbehorighetEgna(ctx context.Context) (*ladoktypes.KataloginformationAnvandarbehorighetEgna, *http.Response, error) {
 	url := fmt.Sprintf("%s/%s", s.service, "anvandarbehorighet/egna")
 	reply := &ladoktypes.KataloginformationAnvandarbehorighetEgna{}
-	resp, err := s.client.call(ctx, s.acceptHeader(), http.MethodGet, url, nil, reply)
+	resp, err := s.client.call(ctx, Endpoint{Service: s.service, Operation: "GetAnvandarbehorighetEgna"}, s.acceptHeader(), http.MethodGet, url, nil, reply)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -64,7 +64,7 @@ func (s *kataloginformationService) GetAnvandarbehorighetEgna(ctx context.Contex
 func (s *kataloginformationService) GetGrunddataLarosatesinformation(ctx context.Context) (*ladoktypes.KataloginformationGrunddataLarosatesinformation, *http.Response, error) {
 	url := fmt.Sprintf("%s/%s/%s", s.service, "grunddata", "larosatesinformation")
 	reply := &ladoktypes.KataloginformationGrunddataLarosatesinformation{}
-	resp, err := s.client.call(ctx, s.acceptHeader(), http.MethodGet, url, nil, reply)
+	resp, err := s.client.call(ctx, Endpoint{Service: s.service, Operation: "GetGrunddataLarosatesinformation"}, s.acceptHeader(), http.MethodGet, url, nil, reply)
 	if err != nil {
 		return nil, resp, err
 	}
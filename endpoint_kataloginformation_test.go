@@ -0,0 +1,55 @@
+package goladok3
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKataloginformationXML(t *testing.T) {
+	mux, server, client := mockSetup(t, ladoktypes.EnvProdAPI)
+	defer server.Close()
+	client.format = FormatXML
+
+	mux.HandleFunc("/kataloginformation/anvandare/autentiserad", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.ladok-kataloginformation+xml", r.Header.Get("Accept"))
+		w.Header().Set("Content-Type", ContentTypeKataloginformationXML)
+		w.Write(ladokmocks.XMLKataloginformationAutentiserad)
+	})
+	mux.HandleFunc("/kataloginformation/behorighetsprofil/09E52B69-5D50-4A62-B65C-636BCA68FAE5", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeKataloginformationXML)
+		w.Write(ladokmocks.XMLKataloginformationBehorighetsprofil)
+	})
+
+	autentiserad, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, ladokmocks.MockKataloginformationAutentiserad(), autentiserad)
+
+	profil, _, err := client.Kataloginformation.GetBehorighetsprofil(context.TODO(), &GetBehorighetsprofilerReq{UID: "09E52B69-5D50-4A62-B65C-636BCA68FAE5"})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	want := ladokmocks.MockKataloginformationBehorighetsprofil()
+	assert.Equal(t, want.Benamning, profil.Benamning)
+	assert.Equal(t, want.LarosateID, profil.LarosateID)
+	assert.Equal(t, want.Rattighetsniva, profil.Rattighetsniva)
+	assert.Equal(t, want.UID, profil.UID)
+	assert.Equal(t, want.Link, profil.Link)
+	if assert.Len(t, profil.Systemaktiviteter, 2) {
+		assert.Equal(t, want.Systemaktiviteter[1].I18NNyckel, profil.Systemaktiviteter[1].I18NNyckel)
+		assert.Equal(t, want.Systemaktiviteter[1].ID, profil.Systemaktiviteter[1].ID)
+		assert.True(t, profil.Systemaktiviteter[1].KlarForProduktion)
+	}
+	if assert.Len(t, profil.Dataavgransningar.Lista, 1) {
+		assert.Equal(t, "ORGANISATION", profil.Dataavgransningar.Lista[0].DataDimension)
+		assert.Equal(t, "01234567-1234-abcd-ef01-1234567890abcd", profil.Dataavgransningar.Lista[0].DataID)
+		assert.Equal(t, "11111111-2222-0000-0000-000000000000", profil.Dataavgransningar.Lista[0].UID)
+		assert.Len(t, profil.Dataavgransningar.Lista[0].Link, 1)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/masv3971/goladok3/ladoktypes"
 )
@@ -42,7 +43,7 @@ func (s *studentinformationService) GetStudent(ctx context.Context, req *GetStud
 		url = fmt.Sprintf("%s/%s/%s/%s", s.service, "student", "externtuuid", req.ExterntUID)
 	}
 
-	resp, err := s.client.call(ctx, s.acceptHeader(), "GET", url, nil, reply)
+	resp, err := s.client.call(ctx, Endpoint{Service: s.service, Operation: "GetStudent"}, s.acceptHeader(), "GET", url, nil, reply)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -60,9 +61,78 @@ func (s *studentinformationService) GetAktivPaLarosate(ctx context.Context, req
 	}
 	url := fmt.Sprintf("%s/%s/%s/%s", s.service, "student", req.UID, "aktivpalarosaten")
 	reply := &ladoktypes.AktivPaLarosate{}
-	resp, err := s.client.call(ctx, s.acceptHeader(), "GET", url, nil, reply)
+	resp, err := s.client.call(ctx, Endpoint{Service: s.service, Operation: "GetAktivPaLarosate"}, s.acceptHeader(), "GET", url, nil, reply)
 	if err != nil {
 		return nil, resp, err
 	}
 	return reply, resp, nil
 }
+
+// GetKontaktuppgifterReq config for GetKontaktuppgifter
+type GetKontaktuppgifterReq struct {
+	UID string `validate:"required"`
+}
+
+// GetKontaktuppgifter returns the contact details of a student
+func (s *studentinformationService) GetKontaktuppgifter(ctx context.Context, req *GetKontaktuppgifterReq) (*ladoktypes.Kontaktuppgifter, *http.Response, error) {
+	if err := Check(req); err != nil {
+		return nil, nil, err
+	}
+	url := fmt.Sprintf("%s/%s/%s/%s", s.service, "student", req.UID, "kontaktuppgifter")
+	reply := &ladoktypes.Kontaktuppgifter{}
+	resp, err := s.client.call(ctx, Endpoint{Service: s.service, Operation: "GetKontaktuppgifter"}, s.acceptHeader(), "GET", url, nil, reply)
+	if err != nil {
+		return nil, resp, err
+	}
+	return reply, resp, nil
+}
+
+// PutKontaktuppgifterReq config for PutKontaktuppgifter
+type PutKontaktuppgifterReq struct {
+	UID              string                       `validate:"required"`
+	Kontaktuppgifter *ladoktypes.Kontaktuppgifter `validate:"required"`
+}
+
+// PutKontaktuppgifter updates the contact details of a student and returns them as saved by ladok
+func (s *studentinformationService) PutKontaktuppgifter(ctx context.Context, req *PutKontaktuppgifterReq) (*ladoktypes.Kontaktuppgifter, *http.Response, error) {
+	if err := Check(req); err != nil {
+		return nil, nil, err
+	}
+	url := fmt.Sprintf("%s/%s/%s/%s", s.service, "student", req.UID, "kontaktuppgifter")
+	reply := &ladoktypes.Kontaktuppgifter{}
+	resp, err := s.client.call(ctx, Endpoint{Service: s.service, Operation: "PutKontaktuppgifter"}, s.acceptHeader(), "PUT", url, req.Kontaktuppgifter, reply)
+	if err != nil {
+		return nil, resp, err
+	}
+	return reply, resp, nil
+}
+
+// SearchStudentReq config for SearchStudent
+type SearchStudentReq struct {
+	Fornamn      string `validate:"required_without_all=Efternamn Personnummer"`
+	Efternamn    string `validate:"required_without_all=Fornamn Personnummer"`
+	Personnummer string `validate:"required_without_all=Fornamn Efternamn"`
+	// Limit is the number of students fetched per page, defaults to DefaultPageLimit
+	Limit int
+}
+
+// SearchStudent returns an Iterator over the students matching req, from student/filtrera
+func (s *studentinformationService) SearchStudent(req *SearchStudentReq) *Iterator[ladoktypes.Student] {
+	if err := Check(req); err != nil {
+		return failedIterator[ladoktypes.Student](err)
+	}
+
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"fornamn":      req.Fornamn,
+		"efternamn":    req.Efternamn,
+		"personnummer": req.Personnummer,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	path := fmt.Sprintf("%s/%s/%s", s.service, "student", "filtrera")
+
+	return searchIterator[ladoktypes.Student](s.client, Endpoint{Service: s.service, Operation: "SearchStudent"}, s.acceptHeader(), path, query, req.Limit)
+}
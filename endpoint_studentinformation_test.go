@@ -1,8 +1,14 @@
 package goladok3
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/masv3971/goladok3/ladokmocks"
 	"github.com/masv3971/goladok3/ladoktypes"
 	"github.com/stretchr/testify/assert"
 )
@@ -43,3 +49,123 @@ func TestGenderString(t *testing.T) {
 		})
 	}
 }
+
+func TestStudentinformationXML(t *testing.T) {
+	mux, server, client := mockSetup(t, ladoktypes.EnvProdAPI)
+	defer server.Close()
+	client.format = FormatXML
+
+	mux.HandleFunc("/studentinformation/student/11111111-2222-0000-0000-000000000000", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.ladok-studentinformation+xml", r.Header.Get("Accept"))
+		w.Header().Set("Content-Type", ContentTypeStudentinformationXML)
+		w.Write(ladokmocks.XMLStudentinformationStudent)
+	})
+	mux.HandleFunc("/studentinformation/student/339A47C0-426D-4012-B83A-6427E9587352/aktivpalarosaten", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeStudentinformationXML)
+		w.Write(ladokmocks.XMLAktivPaLarosate)
+	})
+
+	want := ladokmocks.MockStudentinformationStudent()
+	want.Link = []ladoktypes.Link{
+		{
+			Method:    "GET",
+			URI:       "https://api.ladok.se:443/studentinformation/student/11111111-2222-0000-0000-000000000000",
+			MediaType: "application/vnd.ladok+xml",
+			Rel:       "self",
+		},
+	}
+
+	student, _, err := client.Studentinformation.GetStudent(context.TODO(), &GetStudentReq{UID: "11111111-2222-0000-0000-000000000000"})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, want, student)
+
+	aktiv, _, err := client.Studentinformation.GetAktivPaLarosate(context.TODO(), &GetAktivPaLarosateReq{UID: "339A47C0-426D-4012-B83A-6427E9587352"})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	if assert.Len(t, aktiv.Studentkopplingar, 2) {
+		assert.Equal(t, 39, aktiv.Studentkopplingar[1].LarosateID)
+		assert.Equal(t, "339A47C0-426D-4012-B83A-6427E9587352", aktiv.Studentkopplingar[1].StudentUID)
+	}
+}
+
+func TestPutKontaktuppgifter(t *testing.T) {
+	uid := "11111111-2222-0000-0000-000000000000"
+
+	tts := []struct {
+		name            string
+		format          string
+		contentType     string
+		reply           []byte
+		wantContentType string
+		wantBodyPrefix  string
+	}{
+		{
+			name:            "json",
+			format:          FormatJSON,
+			contentType:     ContentTypeStudentinformationJSON,
+			reply:           ladokmocks.JSONStudentinformationKontaktuppgifter,
+			wantContentType: "application/vnd.ladok-studentinformation+json;charset=UTF-8",
+			wantBodyPrefix:  `{"Epostadress":`,
+		},
+		{
+			name:            "xml",
+			format:          FormatXML,
+			contentType:     ContentTypeStudentinformationXML,
+			reply:           ladokmocks.XMLStudentinformationKontaktuppgifter,
+			wantContentType: "application/vnd.ladok-studentinformation+xml;charset=UTF-8",
+			wantBodyPrefix:  `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<Kontaktuppgifter xmlns="http://schemas.ladok.se/studentinformation">`,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			mux, server, client := mockSetup(t, ladoktypes.EnvIntTestAPI)
+			defer server.Close()
+			client.format = tt.format
+
+			have := ladokmocks.MockStudentinformationKontaktuppgifter()
+			mux.HandleFunc("/studentinformation/student/"+uid+"/kontaktuppgifter", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, "PUT")
+				assert.Equal(t, tt.wantContentType, r.Header.Get("Content-Type"))
+
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.True(t, strings.HasPrefix(string(body), tt.wantBodyPrefix), string(body))
+
+				got := &ladoktypes.Kontaktuppgifter{}
+				decode, err := client.mediaTypes.decoder(tt.contentType)
+				assert.NoError(t, err)
+				assert.NoError(t, decode(bytes.NewReader(body), got))
+				assert.Equal(t, have.Epostadress, got.Epostadress)
+				assert.Equal(t, have.Postadresser, got.Postadresser)
+
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write(tt.reply)
+			})
+
+			got, _, err := client.Studentinformation.PutKontaktuppgifter(context.TODO(), &PutKontaktuppgifterReq{UID: uid, Kontaktuppgifter: have})
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, have.Epostadress, got.Epostadress)
+			assert.Equal(t, have.Telefonnummer, got.Telefonnummer)
+			assert.Equal(t, have.Postadresser, got.Postadresser)
+			assert.Equal(t, uid, got.UID)
+		})
+	}
+}
+
+func TestGetKontaktuppgifter(t *testing.T) {
+	mux, server, client := mockSetup(t, ladoktypes.EnvIntTestAPI)
+	defer server.Close()
+
+	uid := "11111111-2222-0000-0000-000000000000"
+	mockGenericEndpointServer(t, mux, ContentTypeStudentinformationJSON, "GET", "/studentinformation/student/"+uid+"/kontaktuppgifter", ladokmocks.JSONStudentinformationKontaktuppgifter, 200)
+
+	got, _, err := client.Studentinformation.GetKontaktuppgifter(context.TODO(), &GetKontaktuppgifterReq{UID: uid})
+	assert.NoError(t, err)
+	assert.Equal(t, ladokmocks.MockStudentinformationKontaktuppgifter(), got)
+}
@@ -0,0 +1,79 @@
+package goladok3
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/masv3971/goladok3/ladoktypes"
+)
+
+var (
+	// ErrEnvironmentMismatch if the URL belongs to another ladok environment than the client certificate
+	ErrEnvironmentMismatch = errors.New("Ladok environment does not match the URL")
+)
+
+// DefaultBaseURLs holds the ladok api base URL of each environment, used when the config has no URL.
+// X509Config.BaseURLs and OidcConfig.BaseURLs override it per client.
+var DefaultBaseURLs = map[string]string{
+	ladoktypes.EnvIntTestAPI: "https://api.integrationstest.ladok.se",
+	ladoktypes.EnvTestAPI:    "https://api.test.ladok.se",
+	ladoktypes.EnvProdAPI:    "https://api.ladok.se",
+}
+
+// baseURLs returns DefaultBaseURLs with overrides applied
+func baseURLs(overrides map[string]string) map[string]string {
+	urls := make(map[string]string, len(DefaultBaseURLs)+len(overrides))
+	for env, u := range DefaultBaseURLs {
+		urls[env] = u
+	}
+	for env, u := range overrides {
+		urls[env] = u
+	}
+	return urls
+}
+
+// resolveBaseURL returns configured, or the base URL of env if configured is empty.
+// A configured URL known to belong to another environment than env is refused.
+func resolveBaseURL(configured, env string, overrides map[string]string) (string, error) {
+	urls := baseURLs(overrides)
+
+	if configured == "" {
+		if env == "" {
+			return "", fmt.Errorf("no URL configured: %w", ladoktypes.ErrNoEnvFound)
+		}
+		u, ok := urls[env]
+		if !ok {
+			return "", fmt.Errorf("no URL configured for environment %q", env)
+		}
+		return u, nil
+	}
+
+	if env == "" {
+		return configured, nil
+	}
+
+	host, err := urlHost(configured)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", configured, err)
+	}
+	for urlEnv, u := range urls {
+		if urlEnv == env {
+			continue
+		}
+		if knownHost, err := urlHost(u); err == nil && knownHost == host {
+			return "", fmt.Errorf("%w: %s is %s, the client is %s", ErrEnvironmentMismatch, configured, urlEnv, env)
+		}
+	}
+	return configured, nil
+}
+
+// urlHost returns the lower case host name of rawURL
+func urlHost(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(u.Hostname()), nil
+}
@@ -0,0 +1,119 @@
+package goladok3
+
+import (
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveBaseURL(t *testing.T) {
+	tts := []struct {
+		name       string
+		configured string
+		env        string
+		overrides  map[string]string
+		want       string
+		wantErr    error
+	}{
+		{
+			name: "int-test from table",
+			env:  ladoktypes.EnvIntTestAPI,
+			want: "https://api.integrationstest.ladok.se",
+		},
+		{
+			name: "test from table",
+			env:  ladoktypes.EnvTestAPI,
+			want: "https://api.test.ladok.se",
+		},
+		{
+			name: "prod from table",
+			env:  ladoktypes.EnvProdAPI,
+			want: "https://api.ladok.se",
+		},
+		{
+			name:      "overridden",
+			env:       ladoktypes.EnvTestAPI,
+			overrides: map[string]string{ladoktypes.EnvTestAPI: "https://ladok-test.example.com"},
+			want:      "https://ladok-test.example.com",
+		},
+		{
+			name:    "no url and no environment",
+			wantErr: ladoktypes.ErrNoEnvFound,
+		},
+		{
+			name:       "configured url without environment",
+			configured: "https://api.test.ladok.se",
+			want:       "https://api.test.ladok.se",
+		},
+		{
+			name:       "configured url of the same environment",
+			configured: "https://api.ladok.se",
+			env:        ladoktypes.EnvProdAPI,
+			want:       "https://api.ladok.se",
+		},
+		{
+			name:       "prod certificate against test url",
+			configured: "https://api.test.ladok.se",
+			env:        ladoktypes.EnvProdAPI,
+			wantErr:    ErrEnvironmentMismatch,
+		},
+		{
+			name:       "test certificate against prod url",
+			configured: "https://API.ladok.se:443/",
+			env:        ladoktypes.EnvTestAPI,
+			wantErr:    ErrEnvironmentMismatch,
+		},
+		{
+			name:       "prod certificate against overridden test url",
+			configured: "https://ladok-test.example.com",
+			env:        ladoktypes.EnvProdAPI,
+			overrides:  map[string]string{ladoktypes.EnvTestAPI: "https://ladok-test.example.com"},
+			wantErr:    ErrEnvironmentMismatch,
+		},
+		{
+			name:       "unknown url",
+			configured: "https://ladok.example.com",
+			env:        ladoktypes.EnvProdAPI,
+			want:       "https://ladok.example.com",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveBaseURL(tt.configured, tt.env, tt.overrides)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewX509BaseURL(t *testing.T) {
+	certPEM, _, keyPEM, _ := ladokmocks.MockCertificateAndKey(t, ladoktypes.EnvIntTestAPI, 0, 100)
+
+	client, err := NewX509(X509Config{
+		CertificatePEM: certPEM,
+		PrivateKeyPEM:  keyPEM,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "https://api.integrationstest.ladok.se", client.url)
+	}
+
+	_, err = NewX509(X509Config{
+		URL:            "https://api.ladok.se",
+		CertificatePEM: certPEM,
+		PrivateKeyPEM:  keyPEM,
+	})
+	assert.ErrorIs(t, err, ErrEnvironmentMismatch)
+
+	client, err = NewX509(X509Config{
+		CertificatePEM: certPEM,
+		PrivateKeyPEM:  keyPEM,
+		Environment:    ladoktypes.EnvTestAPI,
+		BaseURLs:       map[string]string{ladoktypes.EnvTestAPI: "https://ladok-test.example.com"},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "https://ladok-test.example.com", client.url)
+	}
+}
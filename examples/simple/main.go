@@ -1 +1,47 @@
 package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/masv3971/goladok3"
+	"golang.org/x/time/rate"
+)
+
+func main() {
+	certPath := flag.String("cert", "cert.pem", "client certificate PEM")
+	keyPath := flag.String("key", "key.pem", "client private key PEM")
+	flag.Parse()
+
+	certPEM, err := os.ReadFile(*certPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	keyPEM, err := os.ReadFile(*keyPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client, err := goladok3.New(
+		goladok3.X509Config{
+			CertificatePEM: certPEM,
+			PrivateKeyPEM:  keyPEM,
+		},
+		goladok3.WithRateLimit(rate.Every(time.Second), 10),
+		goladok3.WithUserAgent("goladok3-example"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	anvandare, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(anvandare.Anvandarnamn)
+}
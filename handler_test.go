@@ -228,7 +228,7 @@ func TestHTTPHandlers(t *testing.T) {
 					}
 				case 500:
 					_, _, err = f(context.TODO())
-					if !assert.Equal(t, err, tt.clientReplyType.(*ladoktypes.LadokError)) {
+					if !testLadokError(t, tt.clientReplyType.(*ladoktypes.LadokError), err) {
 						t.FailNow()
 					}
 				}
@@ -246,7 +246,7 @@ func TestHTTPHandlers(t *testing.T) {
 					}
 				case 500:
 					_, _, err = f(context.TODO())
-					assert.Equal(t, err, tt.clientReplyType.(*ladoktypes.LadokError))
+					testLadokError(t, tt.clientReplyType.(*ladoktypes.LadokError), err)
 				}
 			case func(context.Context, *GetBehorighetsprofilerReq) (*ladoktypes.KataloginformationBehorighetsprofil, *http.Response, error):
 				f := tt.clientFn.(func(context.Context, *GetBehorighetsprofilerReq) (*ladoktypes.KataloginformationBehorighetsprofil, *http.Response, error))
@@ -262,7 +262,7 @@ func TestHTTPHandlers(t *testing.T) {
 					}
 				case 500:
 					_, _, err = f(context.TODO(), tt.clientReq.(*GetBehorighetsprofilerReq))
-					assert.Equal(t, err, tt.clientReplyType.(*ladoktypes.LadokError))
+					testLadokError(t, tt.clientReplyType.(*ladoktypes.LadokError), err)
 				}
 			case func(context.Context, *GetStudentReq) (*ladoktypes.Student, *http.Response, error):
 				f := tt.clientFn.(func(context.Context, *GetStudentReq) (*ladoktypes.Student, *http.Response, error))
@@ -278,7 +278,7 @@ func TestHTTPHandlers(t *testing.T) {
 					}
 				case 500:
 					_, _, err = f(context.TODO(), tt.clientReq.(*GetStudentReq))
-					assert.Equal(t, err, tt.clientReplyType.(*ladoktypes.LadokError))
+					testLadokError(t, tt.clientReplyType.(*ladoktypes.LadokError), err)
 				}
 			case func(context.Context, *GetAktivPaLarosateReq) (*ladoktypes.AktivPaLarosate, *http.Response, error):
 				f := tt.clientFn.(func(context.Context, *GetAktivPaLarosateReq) (*ladoktypes.AktivPaLarosate, *http.Response, error))
@@ -295,7 +295,7 @@ func TestHTTPHandlers(t *testing.T) {
 
 				case 500:
 					_, _, err = f(context.TODO(), tt.clientReq.(*GetAktivPaLarosateReq))
-					assert.Equal(t, err, tt.clientReplyType.(*ladoktypes.LadokError))
+					testLadokError(t, tt.clientReplyType.(*ladoktypes.LadokError), err)
 				}
 			case func(context.Context) (*ladoktypes.KataloginformationGrunddataLarosatesinformation, *http.Response, error):
 				f := tt.clientFn.(func(context.Context) (*ladoktypes.KataloginformationGrunddataLarosatesinformation, *http.Response, error))
@@ -312,7 +312,7 @@ func TestHTTPHandlers(t *testing.T) {
 
 				case 500:
 					_, _, err = f(context.TODO())
-					assert.Equal(t, err, tt.clientReplyType.(*ladoktypes.LadokError))
+					testLadokError(t, tt.clientReplyType.(*ladoktypes.LadokError), err)
 				}
 			default:
 				t.Fatalf("ERROR No function signature found! %T", tt.clientFn)
@@ -0,0 +1,159 @@
+package goladok3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/masv3971/goladok3/ladoktypes"
+)
+
+var (
+	// ErrTooManyItems if All finds more items than its cap
+	ErrTooManyItems = errors.New("More items than allowed")
+)
+
+// DefaultPageLimit is the number of items fetched per page when no limit is given
+const DefaultPageLimit = 100
+
+// Iterator pages through a ladok search, fetching the next page when needed.
+// Every page is a request of its own, subject to the client rate limiter.
+//
+//	it := client.Studentinformation.SearchStudent(req)
+//	for it.Next(ctx) {
+//		student := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator[T any] struct {
+	fetch func(ctx context.Context, page, limit int) (*ladoktypes.SearchPage[T], error)
+	limit int
+
+	page    int
+	items   []T
+	index   int
+	total   int
+	fetched int
+	done    bool
+	err     error
+}
+
+// newIterator returns an Iterator calling fetch for page 1, 2, ... with limit items per page
+func newIterator[T any](limit int, fetch func(ctx context.Context, page, limit int) (*ladoktypes.SearchPage[T], error)) *Iterator[T] {
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+	return &Iterator[T]{fetch: fetch, limit: limit, index: -1}
+}
+
+// Next advances to the next item, it returns false when there are no more items, ctx is done or a request failed
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	if it.index+1 < len(it.items) {
+		it.index++
+		return true
+	}
+	if it.done {
+		return false
+	}
+
+	it.page++
+	page, err := it.fetch(ctx, it.page, it.limit)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.items = page.Resultat
+	it.index = 0
+	it.total = page.TotaltAntalPoster
+	it.fetched += len(page.Resultat)
+	// Ladok may return fewer items than the limit, so a short page only ends the search when
+	// TotaltAntalPoster is missing or 0
+	if it.total > 0 {
+		it.done = len(page.Resultat) == 0 || it.fetched >= it.total
+	} else {
+		it.done = len(page.Resultat) < it.limit
+	}
+
+	return len(it.items) > 0
+}
+
+// Item returns the current item
+func (it *Iterator[T]) Item() T {
+	var zero T
+	if it.index < 0 || it.index >= len(it.items) {
+		return zero
+	}
+	return it.items[it.index]
+}
+
+// Err returns the error that stopped Next, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Total returns TotaltAntalPoster of the latest page
+func (it *Iterator[T]) Total() int {
+	return it.total
+}
+
+// All returns the remaining items, at most max of them. If there are more, the first max items
+// are returned together with ErrTooManyItems.
+func (it *Iterator[T]) All(ctx context.Context, max int) ([]T, error) {
+	items := []T{}
+	for it.Next(ctx) {
+		if len(items) == max {
+			return items, fmt.Errorf("%w: more than %d", ErrTooManyItems, max)
+		}
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+// Search returns an Iterator over a paginated ladok search at path of service, like "studentinformation" and
+// "student/filtrera", for searches without a method of their own. Page and limit are added to query.
+// service is given as for Do.
+func Search[T any](c *Client, service, path string, query url.Values, limit int) *Iterator[T] {
+	accept, err := c.serviceAcceptHeader(service)
+	if err != nil {
+		return failedIterator[T](err)
+	}
+	path = fmt.Sprintf("%s/%s", servicePath(service), strings.TrimPrefix(path, "/"))
+
+	return searchIterator[T](c, Endpoint{Service: serviceKey(service), Operation: "Search"}, accept, path, query, limit)
+}
+
+// searchIterator returns an Iterator making a GET request to path with query, page and limit for every page
+func searchIterator[T any](c *Client, endpoint Endpoint, acceptHeader, path string, query url.Values, limit int) *Iterator[T] {
+	return newIterator(limit, func(ctx context.Context, page, limit int) (*ladoktypes.SearchPage[T], error) {
+		q := url.Values{}
+		for key, values := range query {
+			q[key] = append([]string{}, values...)
+		}
+		q.Set("page", strconv.Itoa(page))
+		q.Set("limit", strconv.Itoa(limit))
+
+		reply := &ladoktypes.SearchPage[T]{}
+		if _, err := c.call(ctx, endpoint, acceptHeader, http.MethodGet, path+"?"+q.Encode(), nil, reply); err != nil {
+			return nil, err
+		}
+		return reply, nil
+	})
+}
+
+// failedIterator returns an Iterator stopping with err before any request is made
+func failedIterator[T any](err error) *Iterator[T] {
+	return &Iterator[T]{err: err, index: -1, done: true}
+}
@@ -0,0 +1,166 @@
+package goladok3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSearchServer serves total students, named 1 to total, limit per page from student/filtrera.
+// TotaltAntalPoster is left out unless withTotal.
+// mockSearchServer replies with total students, at most maxLimit per page if maxLimit is not 0
+func mockSearchServer(t *testing.T, total, maxLimit int, withTotal bool, pages *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		assert.Equal(t, "/studentinformation/student/filtrera", r.URL.Path)
+		assert.Equal(t, "Anna", r.URL.Query().Get("fornamn"))
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if maxLimit > 0 && limit > maxLimit {
+			limit = maxLimit
+		}
+		*pages++
+
+		resultat := []ladoktypes.Student{}
+		for i := (page-1)*limit + 1; i <= page*limit && i <= total; i++ {
+			resultat = append(resultat, ladoktypes.Student{Fornamn: "Anna", Efternamn: fmt.Sprint(i)})
+		}
+		reply := map[string]interface{}{"Resultat": resultat}
+		if withTotal {
+			reply["TotaltAntalPoster"] = total
+		}
+		w.Header().Set("Content-Type", ContentTypeStudentinformationJSON)
+		json.NewEncoder(w).Encode(reply)
+	}))
+}
+
+func TestIterator(t *testing.T) {
+	tts := []struct {
+		name      string
+		total     int
+		noTotal   bool
+		limit     int
+		maxLimit  int
+		wantPages int
+	}{
+		{name: "empty", total: 0, limit: 2, wantPages: 1},
+		{name: "one page", total: 2, limit: 5, wantPages: 1},
+		{name: "full last page", total: 4, limit: 2, wantPages: 2},
+		{name: "partial last page", total: 5, limit: 2, wantPages: 3},
+		{name: "no total, partial last page", total: 5, noTotal: true, limit: 2, wantPages: 3},
+		{name: "no total, full last page", total: 4, noTotal: true, limit: 2, wantPages: 3},
+		{name: "pages capped by ladok", total: 10, limit: 5, maxLimit: 3, wantPages: 4},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			pages := 0
+			server := mockSearchServer(t, tt.total, tt.maxLimit, !tt.noTotal, &pages)
+			defer server.Close()
+			client := mockNewClient(t, ladoktypes.EnvIntTestAPI, server.URL)
+
+			it := client.Studentinformation.SearchStudent(&SearchStudentReq{Fornamn: "Anna", Limit: tt.limit})
+			got := []string{}
+			for it.Next(context.TODO()) {
+				got = append(got, it.Item().Efternamn)
+			}
+			assert.NoError(t, it.Err())
+
+			want := []string{}
+			for i := 1; i <= tt.total; i++ {
+				want = append(want, fmt.Sprint(i))
+			}
+			assert.Equal(t, want, got)
+			assert.Equal(t, tt.wantPages, pages)
+			if !tt.noTotal {
+				assert.Equal(t, tt.total, it.Total())
+			}
+			assert.False(t, it.Next(context.TODO()))
+		})
+	}
+}
+
+func TestIteratorAll(t *testing.T) {
+	tts := []struct {
+		name    string
+		total   int
+		max     int
+		want    int
+		wantErr error
+	}{
+		{name: "below cap", total: 5, max: 10, want: 5},
+		{name: "at cap", total: 5, max: 5, want: 5},
+		{name: "above cap", total: 5, max: 3, want: 3, wantErr: ErrTooManyItems},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			pages := 0
+			server := mockSearchServer(t, tt.total, 0, true, &pages)
+			defer server.Close()
+			client := mockNewClient(t, ladoktypes.EnvIntTestAPI, server.URL)
+
+			got, err := client.Studentinformation.SearchStudent(&SearchStudentReq{Fornamn: "Anna", Limit: 2}).All(context.TODO(), tt.max)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Len(t, got, tt.want)
+		})
+	}
+}
+
+func TestIteratorCancel(t *testing.T) {
+	pages := 0
+	server := mockSearchServer(t, 10, 0, true, &pages)
+	defer server.Close()
+	client := mockNewClient(t, ladoktypes.EnvIntTestAPI, server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := client.Studentinformation.SearchStudent(&SearchStudentReq{Fornamn: "Anna", Limit: 2})
+	assert.True(t, it.Next(ctx))
+	cancel()
+
+	assert.False(t, it.Next(ctx))
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+	assert.Equal(t, 1, pages)
+}
+
+func TestSearch(t *testing.T) {
+	pages := 0
+	server := mockSearchServer(t, 3, 0, true, &pages)
+	defer server.Close()
+	client := mockNewClient(t, ladoktypes.EnvIntTestAPI, server.URL)
+
+	got, err := Search[ladoktypes.Student](client, "studentinformation", "/student/filtrera", map[string][]string{"fornamn": {"Anna"}}, 0).All(context.TODO(), 10)
+	assert.NoError(t, err)
+	assert.Len(t, got, 3)
+	assert.Equal(t, 1, pages)
+
+	_, err = Search[ladoktypes.Student](client, "okand", "student/filtrera", nil, 0).All(context.TODO(), 10)
+	assert.ErrorIs(t, err, ErrUnknownService)
+
+	_, err = client.Studentinformation.SearchStudent(&SearchStudentReq{}).All(context.TODO(), 10)
+	assert.Error(t, err)
+}
+
+func TestSearchServicePath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/studiedeltagande/tillfallesdeltagande/filtrera", r.URL.Path)
+		w.Header().Set("Content-Type", ContentTypeStudiedeltagandeJSON)
+		w.Write([]byte(`{"Resultat": [{"Uid": "1"}], "TotaltAntalPoster": 1}`))
+	}))
+	defer server.Close()
+	client := mockNewClient(t, ladoktypes.EnvIntTestAPI, server.URL)
+
+	for _, service := range []string{"studiedeltagande", "studentdeltagande"} {
+		got, err := Search[ladoktypes.Student](client, service, "tillfallesdeltagande/filtrera", nil, 0).All(context.TODO(), 10)
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+	}
+}
@@ -2,21 +2,46 @@ package goladok3
 
 import (
 	"context"
+	"crypto/x509"
+	"errors"
 
 	"github.com/masv3971/goladok3/ladoktypes"
 )
 
-func (c *Client) environment(ctx context.Context) (string, error) {
-	switch c.certificate.Subject.OrganizationalUnit[1] {
-	case ladoktypes.EnvIntTestAPI:
-		return ladoktypes.EnvIntTestAPI, nil
-	case ladoktypes.EnvProdAPI:
-		return ladoktypes.EnvProdAPI, nil
-	case ladoktypes.EnvTestAPI:
-		return ladoktypes.EnvTestAPI, nil
-	default:
+// Environment returns the ladok environment of the client, ladoktypes.EnvIntTestAPI, EnvTestAPI or EnvProdAPI.
+// X509Config.Environment and OidcConfig.Environment take precedence over the OU of the client certificate.
+func (c *Client) Environment() (string, error) {
+	if c.env != "" {
+		return c.env, nil
+	}
+	if c.certificates == nil {
 		return "", ladoktypes.ErrNoEnvFound
 	}
+	return certificateEnvironment(c.certificates.current())
+}
+
+// EnvironmentFromPEM returns the ladok environment of the first certificate in certificatePEM
+func EnvironmentFromPEM(certificatePEM []byte) (string, error) {
+	certificate, err := parseCertificatePEM(certificatePEM)
+	if err != nil {
+		return "", err
+	}
+	return certificateEnvironment(certificate)
+}
+
+// certificateEnvironment returns the ladok environment found among the certificate OUs
+func certificateEnvironment(certificate *x509.Certificate) (string, error) {
+	if certificate == nil {
+		return "", ladoktypes.ErrNoEnvFound
+	}
+
+	for _, ou := range certificate.Subject.OrganizationalUnit {
+		switch ou {
+		case ladoktypes.EnvIntTestAPI, ladoktypes.EnvTestAPI, ladoktypes.EnvProdAPI:
+			return ou, nil
+		}
+	}
+	return "", ladoktypes.ErrNoEnvFound
 }
 
 // StudentDegree is a student degree.
@@ -44,7 +69,7 @@ type IsStudentReq struct {
 	Personnummer string `validate:"required_without_all=UID ExterntUID"`
 }
 
-// IsStudent check if requested user is a student.
+// IsStudent check if requested user is a student, a student not found in ladok is not an error.
 func (c *Client) IsStudent(ctx context.Context, req *IsStudentReq) (bool, error) {
 	getStudentReq := &GetStudentReq{
 		UID:          req.UID,
@@ -53,7 +78,10 @@ func (c *Client) IsStudent(ctx context.Context, req *IsStudentReq) (bool, error)
 	}
 	_, _, err := c.Studentinformation.GetStudent(ctx, getStudentReq)
 	if err != nil {
+		if errors.Is(err, ladoktypes.ErrNotFound) {
+			return false, nil
+		}
 		return false, err
 	}
-	return false, nil
+	return true, nil
 }
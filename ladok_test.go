@@ -2,11 +2,16 @@ package goladok3
 
 import (
 	"bytes"
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -23,15 +28,18 @@ func mockGenericEndpointServer(t *testing.T, mux *http.ServeMux, contentType, me
 	)
 }
 
-func mockNewClient(t *testing.T, env, url string) *Client {
+// mockNewClient returns a client of env sending requests to url, with its X509Config changed by configure
+func mockNewClient(t *testing.T, env, url string, configure ...func(*X509Config)) *Client {
 	certPEM, _, privateKeyPEM, _ := ladokmocks.MockCertificateAndKey(t, env, 0, 100)
 	cfg := X509Config{
 		URL: url,
 		//ProxyURL:       url,
-		//Certificate:    cert,
 		CertificatePEM: certPEM,
 		PrivateKeyPEM:  privateKeyPEM,
 	}
+	for _, fn := range configure {
+		fn(&cfg)
+	}
 	client, err := NewX509(cfg)
 	if !assert.NoError(t, err) {
 		t.FailNow()
@@ -65,3 +73,326 @@ func testBody(t *testing.T, r *http.Request, want string) {
 	got := buffer.String()
 	require.JSONEq(t, want, got)
 }
+
+// testLadokError checks that err carries a LadokError equal to want
+func testLadokError(t *testing.T, want *ladoktypes.LadokError, err error) bool {
+	got := &ladoktypes.LadokError{}
+	if !assert.ErrorAs(t, err, &got) {
+		return false
+	}
+	return assert.Equal(t, want, got)
+}
+
+func TestHTTPError(t *testing.T) {
+	tts := []struct {
+		name           string
+		statusCode     int
+		reply          []byte
+		want           error
+		wantLadokError bool
+	}{
+		{
+			name:           "404 with ladok error",
+			statusCode:     404,
+			reply:          ladokmocks.JSONErrorsValideringsFel,
+			want:           ladoktypes.ErrNotFound,
+			wantLadokError: true,
+		},
+		{
+			name:       "403 without body",
+			statusCode: 403,
+			want:       ladoktypes.ErrForbidden,
+		},
+		{
+			name:       "503 from proxy",
+			statusCode: 503,
+			reply:      []byte("<html>Service Unavailable</html>"),
+			want:       ladoktypes.ErrServiceUnavailable,
+		},
+		{
+			name:           "500 with ladok error",
+			statusCode:     500,
+			reply:          ladokmocks.JSONErrors500,
+			want:           ladoktypes.ErrInternalServerError,
+			wantLadokError: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			mux, server, client := mockSetup(t, ladoktypes.EnvProdAPI)
+			defer server.Close()
+
+			url := fmt.Sprintf("/studentinformation/student/%s", ladokmocks.Students[0].StudentUID)
+			mockGenericEndpointServer(t, mux, ContentTypeStudentinformationJSON, "GET", url, tt.reply, tt.statusCode)
+
+			_, resp, err := client.Studentinformation.GetStudent(context.TODO(), &GetStudentReq{UID: ladokmocks.Students[0].StudentUID})
+			assert.ErrorIs(t, err, tt.want)
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
+
+			httpError := &ladoktypes.HTTPError{}
+			if !assert.ErrorAs(t, err, &httpError) {
+				t.FailNow()
+			}
+			assert.Equal(t, tt.statusCode, httpError.StatusCode)
+			assert.Equal(t, "GET", httpError.Method)
+			assert.Equal(t, server.URL+url, httpError.URL)
+			assert.Equal(t, tt.wantLadokError, httpError.LadokError != nil)
+		})
+	}
+}
+
+func TestIsStudent(t *testing.T) {
+	tts := []struct {
+		name       string
+		statusCode int
+		reply      []byte
+		want       bool
+		wantErr    error
+	}{
+		{
+			name:       "student",
+			statusCode: 200,
+			reply:      ladokmocks.StudentJSON(ladokmocks.Students[0]),
+			want:       true,
+		},
+		{
+			name:       "not a student",
+			statusCode: 404,
+			reply:      ladokmocks.JSONErrorsValideringsFel,
+			want:       false,
+		},
+		{
+			name:       "outage",
+			statusCode: 503,
+			want:       false,
+			wantErr:    ladoktypes.ErrServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			mux, server, client := mockSetup(t, ladoktypes.EnvProdAPI)
+			defer server.Close()
+
+			url := fmt.Sprintf("/studentinformation/student/%s", ladokmocks.Students[0].StudentUID)
+			mockGenericEndpointServer(t, mux, ContentTypeStudentinformationJSON, "GET", url, tt.reply, tt.statusCode)
+
+			got, err := client.IsStudent(context.TODO(), &IsStudentReq{UID: ladokmocks.Students[0].StudentUID})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecodeLadokError(t *testing.T) {
+	want := &ladoktypes.LadokError{
+		FelUID:          ladokmocks.Errors500.FelUID,
+		Felkategori:     ladokmocks.Errors500.Felkategori,
+		FelkategoriText: ladokmocks.Errors500.FelkategoriText,
+		Meddelande:      ladokmocks.Errors500.Meddelande,
+	}
+
+	tts := []struct {
+		name        string
+		contentType string
+		body        []byte
+		want        *ladoktypes.LadokError
+	}{
+		{
+			name:        "json",
+			contentType: ContentTypeKataloginformationJSON,
+			body:        ladokmocks.JSONErrors500,
+			want:        ladokmocks.Errors500,
+		},
+		{
+			name:        "xml",
+			contentType: ContentTypeAtomXML,
+			body:        ladokmocks.XMLErrors500,
+			want:        want,
+		},
+		{
+			name:        "xml labeled as json",
+			contentType: ContentTypeStudentinformationJSON,
+			body:        ladokmocks.XMLErrors500,
+			want:        want,
+		},
+		{
+			name:        "json without content-type",
+			contentType: "",
+			body:        ladokmocks.JSONErrors500,
+			want:        ladokmocks.Errors500,
+		},
+		{
+			name:        "html from proxy",
+			contentType: "text/html; charset=iso-8859-1",
+			body:        []byte("<html><body>Bad Gateway</body></html>"),
+			want:        nil,
+		},
+		{
+			name:        "xml that is not a ladok error",
+			contentType: ContentTypeAtomXML,
+			body:        []byte("<feed></feed>"),
+			want:        nil,
+		},
+		{
+			name:        "empty",
+			contentType: ContentTypeAtomXML,
+			body:        []byte{},
+			want:        nil,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, decodeLadokError(tt.contentType, tt.body))
+		})
+	}
+}
+
+func TestHTTPErrorRawBody(t *testing.T) {
+	mux, server, client := mockSetup(t, ladoktypes.EnvProdAPI)
+	defer server.Close()
+
+	body := []byte("upstream connect error")
+	mockGenericEndpointServer(t, mux, "text/plain", "GET", "/kataloginformation/anvandare/autentiserad", body, 502)
+
+	_, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+
+	httpError := &ladoktypes.HTTPError{}
+	if !assert.ErrorAs(t, err, &httpError) {
+		t.FailNow()
+	}
+	assert.Nil(t, httpError.LadokError)
+	assert.Equal(t, body, httpError.Body)
+	assert.Equal(t, "text/plain", httpError.ContentType)
+}
+
+func TestCertificateEnvironment(t *testing.T) {
+	tts := []struct {
+		name    string
+		ou      []string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "second ou",
+			ou:   []string{"LED", ladoktypes.EnvProdAPI},
+			want: ladoktypes.EnvProdAPI,
+		},
+		{
+			name: "first ou",
+			ou:   []string{ladoktypes.EnvTestAPI, "LED"},
+			want: ladoktypes.EnvTestAPI,
+		},
+		{
+			name: "only ou",
+			ou:   []string{ladoktypes.EnvIntTestAPI},
+			want: ladoktypes.EnvIntTestAPI,
+		},
+		{
+			name:    "no ou",
+			wantErr: ladoktypes.ErrNoEnvFound,
+		},
+		{
+			name:    "unknown ou",
+			ou:      []string{"LED", "Dev-API"},
+			wantErr: ladoktypes.ErrNoEnvFound,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			cert := &x509.Certificate{Subject: pkix.Name{OrganizationalUnit: tt.ou}}
+			got, err := certificateEnvironment(cert)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := certificateEnvironment(nil)
+	assert.ErrorIs(t, err, ladoktypes.ErrNoEnvFound)
+}
+
+func TestEnvironment(t *testing.T) {
+	tts := []struct {
+		name     string
+		certEnv  string
+		override string
+		want     string
+		wantErr  error
+	}{
+		{
+			name:    "from certificate",
+			certEnv: ladoktypes.EnvTestAPI,
+			want:    ladoktypes.EnvTestAPI,
+		},
+		{
+			name:     "override",
+			certEnv:  "",
+			override: ladoktypes.EnvIntTestAPI,
+			want:     ladoktypes.EnvIntTestAPI,
+		},
+		{
+			name:    "none",
+			certEnv: "",
+			wantErr: ladoktypes.ErrNoEnvFound,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			certPEM, _, keyPEM, _ := ladokmocks.MockCertificateAndKey(t, tt.certEnv, 0, 100)
+			client, err := NewX509(X509Config{
+				URL:            "https://ladok.example.com",
+				CertificatePEM: certPEM,
+				PrivateKeyPEM:  keyPEM,
+				Environment:    tt.override,
+			})
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			got, err := client.Environment()
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+
+			if tt.override == "" {
+				got, err = EnvironmentFromPEM(certPEM)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+
+	_, err := NewX509(X509Config{URL: "https://api.ladok.se", CertificatePEM: []byte("x"), PrivateKeyPEM: []byte("x"), Environment: "Dev-API"})
+	assert.Error(t, err)
+}
+
+func TestCompatibilityErrors(t *testing.T) {
+	tts := []struct {
+		name      string
+		status    int
+		wantIs    error
+		wantIsNot error
+	}{
+		{name: "401", status: 401, wantIs: ErrNotAllowedRequest, wantIsNot: ErrInvalidRequest},
+		{name: "500", status: 500, wantIs: ErrInvalidRequest, wantIsNot: ErrNotAllowedRequest},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			mux, server, client := mockSetup(t, ladoktypes.EnvProdAPI)
+			defer server.Close()
+			mockGenericEndpointServer(t, mux, ContentTypeKataloginformationJSON, "GET", "/kataloginformation/anvandare/autentiserad", ladokmocks.JSONErrors500, tt.status)
+
+			_, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+			assert.ErrorIs(t, err, tt.wantIs)
+			assert.NotErrorIs(t, err, tt.wantIsNot)
+		})
+	}
+}
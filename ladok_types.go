@@ -24,6 +24,26 @@ var (
 	// ContentTypeAtomXML server response content type
 	ContentTypeAtomXML = "application/atom+xml;charset=UTF-8"
 
+	// ContentTypeStudiedeltagandeXML server response content type
+	ContentTypeStudiedeltagandeXML = "application/vnd.ladok-studiedeltagande+xml;charset=UTF-8"
+	// ContentTypeKataloginformationXML server response content type
+	ContentTypeKataloginformationXML = "application/vnd.ladok-kataloginformation+xml;charset=UTF-8"
+	// ContentTypeStudentinformationXML server response content type
+	ContentTypeStudentinformationXML = "application/vnd.ladok-studentinformation+xml;charset=UTF-8"
+	// ContentTypeResultatXML server response content type
+	ContentTypeResultatXML = "application/vnd.ladok-resultat+xml;charset=UTF-8"
+	// ContentTypeUppfoljningXML server response content type
+	ContentTypeUppfoljningXML = "application/vnd.ladok-uppfoljning+xml;charset=UTF-8"
+	// ContentTypeExamenXML server response content type
+	ContentTypeExamenXML = "application/vnd.ladok-examen+xml;charset=UTF-8"
+	// ContentTypeUtbildningsinformationXML server response content type
+	ContentTypeUtbildningsinformationXML = "application/vnd.ladok-utbildningsinformation+xml;charset=UTF-8"
+
+	// FormatJSON makes the client request json representations from ladok
+	FormatJSON = "json"
+	// FormatXML makes the client request xml representations from ladok
+	FormatXML = "xml"
+
 	// TypeStudentinformation type
 	TypeStudentinformation = "studentinformation"
 	//  = "externstudentevent"
@@ -0,0 +1,126 @@
+// Package ladokmetrics records the metrics of a goladok3 client with prometheus
+package ladokmetrics
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds the prometheus collectors of a client, it is a goladok3.Metrics
+type Metrics struct {
+	requests       *prometheus.CounterVec
+	duration       *prometheus.HistogramVec
+	rateLimitWait  prometheus.Histogram
+	retries        *prometheus.CounterVec
+	decodeFailures *prometheus.CounterVec
+	feedRecentID   prometheus.Gauge
+	feedCurrentID  prometheus.Gauge
+	feedLag        prometheus.Gauge
+
+	mu       sync.Mutex
+	recentID int
+	current  int
+}
+
+// New creates the client collectors and registers them with registerer, use prometheus.DefaultRegisterer
+// or a prometheus.NewRegistry. Pass it as goladok3.X509Config.Metrics or goladok3.OidcConfig.Metrics.
+func New(registerer prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ladok_requests_total",
+			Help: "Requests made to ladok by service, operation and status code.",
+		}, []string{"service", "operation", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "ladok_request_duration_seconds",
+			Help:    "Latency of ladok requests, including retries and rate limiting, by service, operation and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"service", "operation", "status"}),
+		rateLimitWait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "ladok_rate_limit_wait_seconds",
+			Help:    "Time spent waiting for the client rate limiter before each attempt.",
+			Buckets: []float64{0.001, 0.01, 0.1, 0.5, 1, 2, 5, 10, 30},
+		}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ladok_retries_total",
+			Help: "Retried attempts by service and operation.",
+		}, []string{"service", "operation"}),
+		decodeFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ladok_decode_failures_total",
+			Help: "Successful ladok replies that could not be decoded or parsed, by service and operation.",
+		}, []string{"service", "operation"}),
+		feedRecentID: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ladok_feed_recent_id",
+			Help: "ID of the latest feed read with Recent.",
+		}),
+		feedCurrentID: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ladok_feed_current_id",
+			Help: "ID of the latest feed read with First or Historical.",
+		}),
+		feedLag: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ladok_feed_lag",
+			Help: "Number of feeds between the current feed and the recent feed.",
+		}),
+	}
+
+	for _, collector := range []prometheus.Collector{
+		m.requests,
+		m.duration,
+		m.rateLimitWait,
+		m.retries,
+		m.decodeFailures,
+		m.feedRecentID,
+		m.feedCurrentID,
+		m.feedLag,
+	} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// ObserveRequest records a finished call to operation of service, status is 0 if no response was received
+func (m *Metrics) ObserveRequest(service, operation string, status int, duration time.Duration) {
+	label := "error"
+	if status != 0 {
+		label = strconv.Itoa(status)
+	}
+	m.requests.WithLabelValues(service, operation, label).Inc()
+	m.duration.WithLabelValues(service, operation, label).Observe(duration.Seconds())
+}
+
+// ObserveRateLimitWait records the time waited for the rate limiter before an attempt
+func (m *Metrics) ObserveRateLimitWait(wait time.Duration) {
+	m.rateLimitWait.Observe(wait.Seconds())
+}
+
+// IncRetries counts a retried attempt of operation of service
+func (m *Metrics) IncRetries(service, operation string) {
+	m.retries.WithLabelValues(service, operation).Inc()
+}
+
+// IncDecodeFailures counts a successful reply of operation of service that could not be decoded or parsed
+func (m *Metrics) IncDecodeFailures(service, operation string) {
+	m.decodeFailures.WithLabelValues(service, operation).Inc()
+}
+
+// ObserveFeed records the id of a feed read by operation, Recent or First and Historical
+func (m *Metrics) ObserveFeed(operation string, id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if operation == "Recent" {
+		m.recentID = id
+		m.feedRecentID.Set(float64(id))
+	} else {
+		m.current = id
+		m.feedCurrentID.Set(float64(id))
+	}
+	if m.recentID != 0 && m.current != 0 {
+		m.feedLag.Set(float64(m.recentID - m.current))
+	}
+}
@@ -0,0 +1,76 @@
+package ladokmetrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := New(registry)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	metrics.ObserveRequest("kataloginformation", "GetAnvandareAutentiserad", 200, time.Millisecond)
+	metrics.ObserveRequest("studentinformation", "GetStudent", 500, time.Millisecond)
+	metrics.ObserveRequest("studentinformation", "GetStudent", 0, time.Millisecond)
+	metrics.IncRetries("kataloginformation", "GetAnvandareAutentiserad")
+	metrics.IncDecodeFailures("kataloginformation", "GetAnvandarbehorighetEgna")
+	metrics.ObserveRateLimitWait(time.Millisecond)
+	metrics.ObserveRateLimitWait(time.Millisecond)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requests.WithLabelValues("kataloginformation", "GetAnvandareAutentiserad", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requests.WithLabelValues("studentinformation", "GetStudent", "500")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requests.WithLabelValues("studentinformation", "GetStudent", "error")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.decodeFailures.WithLabelValues("kataloginformation", "GetAnvandarbehorighetEgna")))
+	assert.Equal(t, 3, testutil.CollectAndCount(metrics.duration))
+
+	err = testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP ladok_retries_total Retried attempts by service and operation.
+# TYPE ladok_retries_total counter
+ladok_retries_total{operation="GetAnvandareAutentiserad",service="kataloginformation"} 1
+`), "ladok_retries_total")
+	assert.NoError(t, err)
+
+	families, err := registry.Gather()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	for _, family := range families {
+		if family.GetName() == "ladok_rate_limit_wait_seconds" {
+			assert.Equal(t, uint64(2), family.GetMetric()[0].GetHistogram().GetSampleCount())
+		}
+	}
+}
+
+func TestMetricsFeed(t *testing.T) {
+	metrics, err := New(prometheus.NewRegistry())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	metrics.ObserveFeed("Recent", 110)
+	assert.Equal(t, float64(110), testutil.ToFloat64(metrics.feedRecentID))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.feedLag))
+
+	metrics.ObserveFeed("Historical", 100)
+	assert.Equal(t, float64(100), testutil.ToFloat64(metrics.feedCurrentID))
+	assert.Equal(t, float64(10), testutil.ToFloat64(metrics.feedLag))
+
+	metrics.ObserveFeed("First", 110)
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.feedLag))
+}
+
+func TestMetricsRegisterTwice(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	_, err := New(registry)
+	assert.NoError(t, err)
+	_, err = New(registry)
+	assert.Error(t, err)
+}
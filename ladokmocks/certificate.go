@@ -1,6 +1,7 @@
 package ladokmocks
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -147,3 +148,36 @@ func MockCertificateAndKey(t *testing.T, env string, notBefore, notAfter int) ([
 	//	}
 	//return clientCert, certPrivateKey, signRootCA
 }
+
+// MockCertificateChain return mock client certificate for privateKey, RSA or ECDSA, together with the CA certificate that issued it
+func MockCertificateChain(t *testing.T, env string, privateKey crypto.Signer, notBefore, notAfter int) (*x509.Certificate, *x509.Certificate) {
+	_, caPrivateKey, signRootCA := mockCACertificateAndKey(t)
+
+	certTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2301),
+		Issuer:       signRootCA[0].Subject,
+		Subject: pkix.Name{
+			Country:            []string{"SE"},
+			Organization:       []string{"Ladok"},
+			OrganizationalUnit: []string{"LED", env},
+			Locality:           []string{"Stockholm"},
+			CommonName:         "sunet@KF",
+		},
+		NotBefore:   time.Now().AddDate(0, 0, notBefore),
+		NotAfter:    time.Now().AddDate(0, 0, notAfter),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	certDERByte, err := x509.CreateCertificate(rand.Reader, certTemplate, signRootCA[0], privateKey.Public(), caPrivateKey)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	clientCert, err := x509.ParseCertificate(certDERByte)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return clientCert, signRootCA[0]
+}
@@ -36,3 +36,12 @@ var Errors500 = &ladoktypes.LadokError{
 	Meddelande:      "java.lang.NullPointerException null",
 	Link:            []interface{}{},
 }
+
+// XMLErrors500 ladok error, as sent by xml speaking endpoints like the atom feed
+var XMLErrors500 = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Error xmlns="http://schemas.ladok.se">
+	<FelUID>c0f52d2c-3a5f-11ec-aa00-acd34b504da7</FelUID>
+	<Felkategori>commons.fel.kategori.applikationsfel</Felkategori>
+	<FelkategoriText>Generellt fel i applikationen</FelkategoriText>
+	<Meddelande>java.lang.NullPointerException null</Meddelande>
+</Error>`)
@@ -179,6 +179,50 @@ var JSONKataloginformationBehorighetsprofil = []byte(`
   }
 `)
 
+// XMLKataloginformationBehorighetsprofil mock ladok reply, same profile as JSONKataloginformationBehorighetsprofil with a Dataavgransning
+var XMLKataloginformationBehorighetsprofil = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<ki:Behorighetsprofil xmlns:ki="http://schemas.ladok.se/kataloginformation" xmlns:base="http://schemas.ladok.se">
+	<base:link method="GET" uri="https://api.integrationstest.ladok.se:443/kataloginformation/behorighetsprofil/09E52B69-5D50-4A62-B65C-636BCA68FAE5" mediaType="application/vnd.ladok+xml,application/vnd.ladok-kataloginformation+xml,application/vnd.ladok-kataloginformation+json" rel="self"/>
+	<base:Uid>09E52B69-5D50-4A62-B65C-636BCA68FAE5</base:Uid>
+	<ki:Benamning>
+		<base:Benamning>
+			<base:Sprakkod>sv</base:Sprakkod>
+			<base:Text>Intergration-Sunet</base:Text>
+		</base:Benamning>
+		<base:Benamning>
+			<base:Sprakkod>en</base:Sprakkod>
+			<base:Text>Intergration-Sunet</base:Text>
+		</base:Benamning>
+	</ki:Benamning>
+	<ki:Dataavgransningar>
+		<ki:Lista>
+			<base:link method="POST" uri="https://api.mit.ladok.se:443/test" mediaType="application/vnd.ladok+xml" rel="http://schemas.ladok.se"/>
+			<base:SenastAndradAv>testMail@example.com</base:SenastAndradAv>
+			<base:SenastSparad>2012-01-11T12:45:45</base:SenastSparad>
+			<base:Uid>11111111-2222-0000-0000-000000000000</base:Uid>
+			<ki:DataDimension>ORGANISATION</ki:DataDimension>
+			<ki:DataId>01234567-1234-abcd-ef01-1234567890abcd</ki:DataId>
+			<ki:LarosateID>27</ki:LarosateID>
+		</ki:Lista>
+	</ki:Dataavgransningar>
+	<ki:LarosateID>27</ki:LarosateID>
+	<ki:Rattighetsniva>rattighetsniva.las</ki:Rattighetsniva>
+	<ki:Systemaktiviteter>
+		<ki:Betafunktion>false</ki:Betafunktion>
+		<ki:I18nNyckel>systemaktivitet.uppfoljning.feeds</ki:I18nNyckel>
+		<ki:Id>90019</ki:Id>
+		<ki:KlarForProduktion>true</ki:KlarForProduktion>
+		<ki:Rattighetsniva>rattighetsniva.las</ki:Rattighetsniva>
+	</ki:Systemaktiviteter>
+	<ki:Systemaktiviteter>
+		<ki:Betafunktion>false</ki:Betafunktion>
+		<ki:I18nNyckel>systemaktivitet.studiedeltagande.las</ki:I18nNyckel>
+		<ki:Id>51001</ki:Id>
+		<ki:KlarForProduktion>true</ki:KlarForProduktion>
+		<ki:Rattighetsniva>rattighetsniva.las</ki:Rattighetsniva>
+	</ki:Systemaktiviteter>
+</ki:Behorighetsprofil>`)
+
 //MockKataloginformationBehorighetsprofil return mock ladok KataloginformationBehorighetsprofil
 func MockKataloginformationBehorighetsprofil() *ladoktypes.KataloginformationBehorighetsprofil {
 	s := &ladoktypes.KataloginformationBehorighetsprofil{}
@@ -205,6 +249,19 @@ var JSONKataloginformationAutentiserad = []byte(`
 	  }
 `)
 
+// XMLKataloginformationAutentiserad mock ladok reply, same user as JSONKataloginformationAutentiserad
+var XMLKataloginformationAutentiserad = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<ki:Anvandare xmlns:ki="http://schemas.ladok.se/kataloginformation" xmlns:base="http://schemas.ladok.se">
+	<base:link method="POST" uri="https://api.mit.ladok.se:443/test" mediaType="application/vnd.ladok+xml" rel="http://schemas.ladok.se"/>
+	<base:SenastAndradAv>name@ladok3.ladok.se</base:SenastAndradAv>
+	<base:SenastSparad>2012-01-11T12:45:45</base:SenastSparad>
+	<base:Uid>11111111-2222-0000-0000-000000000000</base:Uid>
+	<ki:Anvandarnamn>mail@school.se</ki:Anvandarnamn>
+	<ki:Efternamn>testEfternamn</ki:Efternamn>
+	<ki:Fornamn>testFornamn</ki:Fornamn>
+	<ki:LarosateID>96</ki:LarosateID>
+</ki:Anvandare>`)
+
 // MockKataloginformationAutentiserad return ladok mock
 func MockKataloginformationAutentiserad() *ladoktypes.KataloginformationAnvandareAutentiserad {
 	s := &ladoktypes.KataloginformationAnvandareAutentiserad{}
@@ -25,6 +25,38 @@ var JSONStudentinformationStudent = []byte(`
 }
 `)
 
+// XMLStudentinformationStudent mock ladok reply, same student as JSONStudentinformationStudent
+var XMLStudentinformationStudent = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<si:Student xmlns:si="http://schemas.ladok.se/studentinformation" xmlns:base="http://schemas.ladok.se">
+	<base:link method="GET" uri="https://api.ladok.se:443/studentinformation/student/11111111-2222-0000-0000-000000000000" mediaType="application/vnd.ladok+xml" rel="self"/>
+	<base:SenastAndradAv>testEppn@ladok3.ladok.se</base:SenastAndradAv>
+	<base:SenastSparad>2012-01-11T12:45:45</base:SenastSparad>
+	<base:Uid>11111111-2222-0000-0000-000000000000</base:Uid>
+	<si:Avliden>false</si:Avliden>
+	<si:Efternamn>TestEfternamn</si:Efternamn>
+	<si:ExterntUID>11111111-2222-0000-0000-000000000000</si:ExterntUID>
+	<si:FelVidEtableringExternt>false</si:FelVidEtableringExternt>
+	<si:Fodelsedata>1996-11-05</si:Fodelsedata>
+	<si:FolkbokforingsbevakningTillOchMed>2020-03-13</si:FolkbokforingsbevakningTillOchMed>
+	<si:Fornamn>TestFornamn</si:Fornamn>
+	<si:KonID>1</si:KonID>
+	<si:LarosateID>96</si:LarosateID>
+	<si:Personnummer>199611052383</si:Personnummer>
+</si:Student>`)
+
+// XMLAktivPaLarosate mock ladok reply
+var XMLAktivPaLarosate = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<si:AktivPaLarosate xmlns:si="http://schemas.ladok.se/studentinformation" xmlns:base="http://schemas.ladok.se">
+	<si:Studentkopplingar>
+		<si:LarosateID>27</si:LarosateID>
+		<si:StudentUID>339A47C0-426D-4012-B83A-6427E9587352</si:StudentUID>
+	</si:Studentkopplingar>
+	<si:Studentkopplingar>
+		<si:LarosateID>39</si:LarosateID>
+		<si:StudentUID>339A47C0-426D-4012-B83A-6427E9587352</si:StudentUID>
+	</si:Studentkopplingar>
+</si:AktivPaLarosate>`)
+
 // JSONAktivPaLarosate mock ladok reply
 var JSONAktivPaLarosate = []byte(` 
 		{
@@ -51,6 +83,53 @@ func MockStudentinformationStudent() *ladoktypes.Student {
 	return s
 }
 
+// JSONStudentinformationKontaktuppgifter mock ladok reply
+var JSONStudentinformationKontaktuppgifter = []byte(`
+{
+	"Epostadress": "student@example.com",
+	"Postadresser": [
+		{
+			"CareOf": "",
+			"Land": "Sverige",
+			"PostadressTyp": "POSTADRESS",
+			"Postnummer": "11122",
+			"Postort": "Stockholm",
+			"Utdelningsadress": "Testgatan 1"
+		}
+	],
+	"Telefonnummer": "070-1234567",
+	"SenastAndradAv": "testEppn@ladok3.ladok.se",
+	"SenastSparad": "2021-05-06T10:11:12",
+	"Uid": "11111111-2222-0000-0000-000000000000"
+}
+`)
+
+// XMLStudentinformationKontaktuppgifter mock ladok reply, same contact details as JSONStudentinformationKontaktuppgifter
+var XMLStudentinformationKontaktuppgifter = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<si:Kontaktuppgifter xmlns:si="http://schemas.ladok.se/studentinformation" xmlns:base="http://schemas.ladok.se">
+	<base:SenastAndradAv>testEppn@ladok3.ladok.se</base:SenastAndradAv>
+	<base:SenastSparad>2021-05-06T10:11:12</base:SenastSparad>
+	<base:Uid>11111111-2222-0000-0000-000000000000</base:Uid>
+	<si:Epostadress>student@example.com</si:Epostadress>
+	<si:Postadresser>
+		<si:CareOf></si:CareOf>
+		<si:Land>Sverige</si:Land>
+		<si:PostadressTyp>POSTADRESS</si:PostadressTyp>
+		<si:Postnummer>11122</si:Postnummer>
+		<si:Postort>Stockholm</si:Postort>
+		<si:Utdelningsadress>Testgatan 1</si:Utdelningsadress>
+	</si:Postadresser>
+	<si:Telefonnummer>070-1234567</si:Telefonnummer>
+</si:Kontaktuppgifter>
+`)
+
+// MockStudentinformationKontaktuppgifter return contact details of JSONStudentinformationKontaktuppgifter
+func MockStudentinformationKontaktuppgifter() *ladoktypes.Kontaktuppgifter {
+	k := &ladoktypes.Kontaktuppgifter{}
+	json.Unmarshal(JSONStudentinformationKontaktuppgifter, k)
+	return k
+}
+
 // StudentJSON return JSON object of a student
 func StudentJSON(studentData StudentData) []byte {
 	s := &ladoktypes.Student{}
@@ -3,6 +3,7 @@ package ladoktypes
 import (
 	"errors"
 	"fmt"
+	"net/http"
 )
 
 var (
@@ -14,19 +15,100 @@ var (
 	ErrNotSufficientPermissions = PermissionErrors{{Msg: "No permissions found in ladok"}}
 	// ErrNoPermissionProvided when input Permission is empty
 	ErrNoPermissionProvided = PermissionErrors{{Msg: "No permissions provided"}}
+
+	// ErrNotModified matches a HTTPError with status 304, which is only expected by a client with a cache
+	ErrNotModified = errors.New("Not modified")
+	// ErrBadRequest matches a HTTPError with status 400
+	ErrBadRequest = errors.New("Bad request")
+	// ErrUnauthorized matches a HTTPError with status 401
+	ErrUnauthorized = errors.New("Unauthorized")
+	// ErrForbidden matches a HTTPError with status 403
+	ErrForbidden = errors.New("Forbidden")
+	// ErrNotFound matches a HTTPError with status 404
+	ErrNotFound = errors.New("Not found")
+	// ErrConflict matches a HTTPError with status 409
+	ErrConflict = errors.New("Conflict")
+	// ErrRateLimited matches a HTTPError with status 429
+	ErrRateLimited = errors.New("Rate limited")
+	// ErrInternalServerError matches a HTTPError with status 500
+	ErrInternalServerError = errors.New("Internal server error")
+	// ErrServiceUnavailable matches a HTTPError with status 502, 503 or 504
+	ErrServiceUnavailable = errors.New("Service unavailable")
+
+	// ErrInvalidRequest matches a HTTPError with any status but 401, it is goladok3.ErrInvalidRequest
+	ErrInvalidRequest = errors.New("Invalid request")
+	// ErrNotAllowedRequest matches a HTTPError with status 401, it is goladok3.ErrNotAllowedRequest
+	ErrNotAllowedRequest = errors.New("Not allowed request")
 )
 
-// FromLadokError returns error by Ladok
+var statusErrors = map[int]error{
+	http.StatusNotModified:         ErrNotModified,
+	http.StatusBadRequest:          ErrBadRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusConflict:            ErrConflict,
+	http.StatusTooManyRequests:     ErrRateLimited,
+	http.StatusInternalServerError: ErrInternalServerError,
+	http.StatusBadGateway:          ErrServiceUnavailable,
+	http.StatusServiceUnavailable:  ErrServiceUnavailable,
+	http.StatusGatewayTimeout:      ErrServiceUnavailable,
+}
+
+// HTTPError is returned when ladok replies with a non-successful http status
+type HTTPError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Header     http.Header
+	// ContentType is the content-type of the error body
+	ContentType string
+	// Body is the raw error body, kept when it could not be decoded into a LadokError
+	Body []byte
+	// LadokError is the decoded error body, nil if ladok did not send one
+	LadokError *LadokError
+}
+
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.LadokError != nil {
+		msg = fmt.Sprintf("%s, %s", msg, e.LadokError.Error())
+	}
+	return msg
+}
+
+// Unwrap returns the decoded ladok error, if any
+func (e *HTTPError) Unwrap() error {
+	if e.LadokError == nil {
+		return nil
+	}
+	return e.LadokError
+}
+
+// Is makes errors.Is match the status sentinels, like ErrNotFound, and the
+// ErrInvalidRequest and ErrNotAllowedRequest returned before HTTPError
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotAllowedRequest:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrInvalidRequest:
+		return e.StatusCode != http.StatusUnauthorized
+	}
+	sentinel, ok := statusErrors[e.StatusCode]
+	return ok && sentinel == target
+}
+
+// LadokError returns error by Ladok, sent as either json or xml
 type LadokError struct {
-	Detaljkod       string        `json:"Detaljkod"`
-	DetaljkodText   string        `json:"DetaljkodText"`
-	FelUID          string        `json:"FelUID"`
-	Felgrupp        string        `json:"Felgrupp"`
-	FelgruppText    string        `json:"FelgruppText"`
-	Felkategori     string        `json:"Felkategori"`
-	FelkategoriText string        `json:"FelkategoriText"`
-	Meddelande      string        `json:"Meddelande"`
-	Link            []interface{} `json:"link"`
+	Detaljkod       string        `json:"Detaljkod" xml:"Detaljkod"`
+	DetaljkodText   string        `json:"DetaljkodText" xml:"DetaljkodText"`
+	FelUID          string        `json:"FelUID" xml:"FelUID"`
+	Felgrupp        string        `json:"Felgrupp" xml:"Felgrupp"`
+	FelgruppText    string        `json:"FelgruppText" xml:"FelgruppText"`
+	Felkategori     string        `json:"Felkategori" xml:"Felkategori"`
+	FelkategoriText string        `json:"FelkategoriText" xml:"FelkategoriText"`
+	Meddelande      string        `json:"Meddelande" xml:"Meddelande"`
+	Link            []interface{} `json:"link" xml:"-"`
 }
 
 func NewLadokError() LadokError {
@@ -43,6 +125,11 @@ func NewLadokError() LadokError {
 	}
 }
 
+// IsZero reports if f carries no information from ladok
+func (f *LadokError) IsZero() bool {
+	return f == nil || (f.FelUID == "" && f.Felkategori == "" && f.Detaljkod == "" && f.Meddelande == "")
+}
+
 func (f *LadokError) Error() string {
 	if f == nil {
 		return ""
@@ -51,6 +138,40 @@ func (f *LadokError) Error() string {
 	return fmt.Sprintf("felUID: %q, detaljkod_text: %q", f.FelUID, f.DetaljkodText)
 }
 
+// RetryError is returned when a request still fails after more than one attempt
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (r *RetryError) Error() string {
+	return fmt.Sprintf("request failed after %d attempts: %v", r.Attempts, r.Err)
+}
+
+// Unwrap returns the error from the last attempt
+func (r *RetryError) Unwrap() error {
+	return r.Err
+}
+
+// ServerCertificateError is returned when the server certificate of ladok can not be verified
+type ServerCertificateError struct {
+	Host   string
+	Reason string
+	Err    error
+}
+
+func (s *ServerCertificateError) Error() string {
+	if s.Err != nil {
+		return fmt.Sprintf("ladok server certificate for %q: %s: %v", s.Host, s.Reason, s.Err)
+	}
+	return fmt.Sprintf("ladok server certificate for %q: %s", s.Host, s.Reason)
+}
+
+// Unwrap returns the underlying tls error, if any
+func (s *ServerCertificateError) Unwrap() error {
+	return s.Err
+}
+
 type PermissionError struct {
 	Msg                 string `json:"msg"`
 	MissingPermissionID int64  `json:"missing_permission_id"`
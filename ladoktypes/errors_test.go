@@ -80,3 +80,81 @@ func TestErrorToString(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPError(t *testing.T) {
+	tts := []struct {
+		name      string
+		have      *HTTPError
+		wantIs    error
+		wantIsNot error
+		want      string
+	}{
+		{
+			name: "not found with ladok error",
+			have: &HTTPError{
+				StatusCode: 404,
+				Method:     "GET",
+				URL:        "https://api.ladok.se/studentinformation/student/1",
+				LadokError: &LadokError{FelUID: "testFelUID", DetaljkodText: "testDetaljkodText"},
+			},
+			wantIs:    ErrNotFound,
+			wantIsNot: ErrForbidden,
+			want:      "GET https://api.ladok.se/studentinformation/student/1: 404 Not Found, felUID: \"testFelUID\", detaljkod_text: \"testDetaljkodText\"",
+		},
+		{
+			name: "rate limited",
+			have: &HTTPError{
+				StatusCode: 429,
+				Method:     "GET",
+				URL:        "https://api.ladok.se/feed/recent",
+			},
+			wantIs:    ErrRateLimited,
+			wantIsNot: ErrServiceUnavailable,
+			want:      "GET https://api.ladok.se/feed/recent: 429 Too Many Requests",
+		},
+		{
+			name: "bad gateway",
+			have: &HTTPError{
+				StatusCode: 502,
+				Method:     "GET",
+				URL:        "https://api.ladok.se/feed/recent",
+			},
+			wantIs:    ErrServiceUnavailable,
+			wantIsNot: ErrInternalServerError,
+			want:      "GET https://api.ladok.se/feed/recent: 502 Bad Gateway",
+		},
+		{
+			name: "unauthorized as before HTTPError",
+			have: &HTTPError{
+				StatusCode: 401,
+				Method:     "GET",
+				URL:        "https://api.ladok.se/feed/recent",
+			},
+			wantIs:    ErrNotAllowedRequest,
+			wantIsNot: ErrInvalidRequest,
+			want:      "GET https://api.ladok.se/feed/recent: 401 Unauthorized",
+		},
+		{
+			name: "internal server error as before HTTPError",
+			have: &HTTPError{
+				StatusCode: 500,
+				Method:     "GET",
+				URL:        "https://api.ladok.se/feed/recent",
+			},
+			wantIs:    ErrInvalidRequest,
+			wantIsNot: ErrNotAllowedRequest,
+			want:      "GET https://api.ladok.se/feed/recent: 500 Internal Server Error",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.have.Error())
+			assert.True(t, errors.Is(tt.have, tt.wantIs))
+			assert.False(t, errors.Is(tt.have, tt.wantIsNot))
+
+			ladokError := &LadokError{}
+			assert.Equal(t, tt.have.LadokError != nil, errors.As(tt.have, &ladokError))
+		})
+	}
+}
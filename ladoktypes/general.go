@@ -1,18 +1,73 @@
 package ladoktypes
 
+import (
+	"encoding/xml"
+	"strings"
+)
+
 // Link is a general ladok link structure
 type Link struct {
-	Method    string `json:"method"`
-	URI       string `json:"uri"`
-	MediaType string `json:"mediaType"`
-	Rel       string `json:"rel"`
+	Method    string `json:"method" xml:"method,attr"`
+	URI       string `json:"uri" xml:"uri,attr"`
+	MediaType string `json:"mediaType" xml:"mediaType,attr"`
+	Rel       string `json:"rel" xml:"rel,attr"`
+}
+
+// FindLink returns the link with relation rel, matching either the whole rel or its last path segment,
+// so "student" finds "http://relations.ladok.se/studentinformation/student"
+func FindLink(links []Link, rel string) (Link, bool) {
+	for _, link := range links {
+		if link.Rel == rel || strings.HasSuffix(link.Rel, "/"+rel) {
+			return link, true
+		}
+	}
+	return Link{}, false
 }
 
 // Benamning is a general ladok Benamning structure
 type Benamning struct {
-	Sprakkod string     `json:"Sprakkod"`
-	Text     string     `json:"Text"`
-	Link     []struct{} `json:"link"`
+	Sprakkod string     `json:"Sprakkod" xml:"Sprakkod"`
+	Text     string     `json:"Text" xml:"Text"`
+	Link     []struct{} `json:"link" xml:"-"`
+}
+
+// Benamningar is a ladok text in swedish and english, {"sv": "", "en": ""} in json and a list of Benamning in xml
+type Benamningar struct {
+	Sv string `json:"sv,omitempty"`
+	En string `json:"en,omitempty"`
+}
+
+// UnmarshalXML reads the Benamning elements of start
+func (b *Benamningar) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s := struct {
+		Benamning []Benamning `xml:"Benamning"`
+	}{}
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	for _, benamning := range s.Benamning {
+		switch benamning.Sprakkod {
+		case "sv":
+			b.Sv = benamning.Text
+		case "en":
+			b.En = benamning.Text
+		}
+	}
+	return nil
+}
+
+// MarshalXML writes b as Benamning elements of start
+func (b Benamningar) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	s := struct {
+		Benamning []Benamning `xml:"Benamning"`
+	}{}
+	if b.Sv != "" {
+		s.Benamning = append(s.Benamning, Benamning{Sprakkod: "sv", Text: b.Sv})
+	}
+	if b.En != "" {
+		s.Benamning = append(s.Benamning, Benamning{Sprakkod: "en", Text: b.En})
+	}
+	return e.EncodeElement(s, start)
 }
 
 const (
@@ -23,3 +78,9 @@ const (
 	// EnvTestAPI ladok test environment
 	EnvTestAPI = "Test-API"
 )
+
+// SearchPage is one page of a paginated ladok search, like student/filtrera
+type SearchPage[T any] struct {
+	Resultat          []T `json:"Resultat" xml:"Resultat"`
+	TotaltAntalPoster int `json:"TotaltAntalPoster" xml:"TotaltAntalPoster"`
+}
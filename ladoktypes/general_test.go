@@ -0,0 +1,69 @@
+package ladoktypes
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindLink(t *testing.T) {
+	links := []Link{
+		{Rel: "self", URI: "https://api.ladok.se/studentinformation/student/1"},
+		{Rel: "http://relations.ladok.se/studentinformation/student", URI: "https://api.ladok.se/studentinformation/student/2"},
+	}
+
+	tts := []struct {
+		rel     string
+		wantURI string
+		wantOK  bool
+	}{
+		{rel: "self", wantURI: "https://api.ladok.se/studentinformation/student/1", wantOK: true},
+		{rel: "student", wantURI: "https://api.ladok.se/studentinformation/student/2", wantOK: true},
+		{rel: "http://relations.ladok.se/studentinformation/student", wantURI: "https://api.ladok.se/studentinformation/student/2", wantOK: true},
+		{rel: "dent", wantOK: false},
+		{rel: "kurs", wantOK: false},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.rel, func(t *testing.T) {
+			got, ok := FindLink(links, tt.rel)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantURI, got.URI)
+		})
+	}
+}
+
+func TestBenamningarXML(t *testing.T) {
+	tts := []struct {
+		name string
+		have Benamningar
+		want string
+	}{
+		{
+			name: "sv and en",
+			have: Benamningar{Sv: "Svenska", En: "English"},
+			want: `<Benamning><Benamning><Sprakkod>sv</Sprakkod><Text>Svenska</Text></Benamning><Benamning><Sprakkod>en</Sprakkod><Text>English</Text></Benamning></Benamning>`,
+		},
+		{
+			name: "sv",
+			have: Benamningar{Sv: "Svenska"},
+			want: `<Benamning><Benamning><Sprakkod>sv</Sprakkod><Text>Svenska</Text></Benamning></Benamning>`,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			type profil struct {
+				Benamning Benamningar `xml:"Benamning"`
+			}
+			got, err := xml.Marshal(profil{Benamning: tt.have})
+			assert.NoError(t, err)
+			assert.Equal(t, "<profil>"+tt.want+"</profil>", string(got))
+
+			s := profil{}
+			assert.NoError(t, xml.Unmarshal(got, &s))
+			assert.Equal(t, tt.have, s.Benamning)
+		})
+	}
+}
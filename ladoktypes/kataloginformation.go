@@ -2,115 +2,119 @@ package ladoktypes
 
 // KataloginformationAnvandareAutentiserad is ladok response from /kataloginformation/anvandare/autentiserad
 type KataloginformationAnvandareAutentiserad struct {
-	Anvandarnamn   string `json:"Anvandarnamn"`
-	Efternamen     string `json:"Efternamn"`
-	Fornamn        string `json:"Fornamn"`
-	SenastAndradAv string `json:"SenastAndradAv"`
-	SenastSparad   string `json:"SenastSparad"`
-	LarosateID     int    `json:"LarosateID"`
-	UID            string `json:"Uid"`
-	Link           []Link `json:"link"`
+	Anvandarnamn   string `json:"Anvandarnamn" xml:"Anvandarnamn"`
+	Efternamen     string `json:"Efternamn" xml:"Efternamn"`
+	Fornamn        string `json:"Fornamn" xml:"Fornamn"`
+	SenastAndradAv string `json:"SenastAndradAv" xml:"SenastAndradAv"`
+	SenastSparad   string `json:"SenastSparad" xml:"SenastSparad"`
+	LarosateID     int    `json:"LarosateID" xml:"LarosateID"`
+	UID            string `json:"Uid" xml:"Uid"`
+	Link           []Link `json:"link" xml:"link"`
 }
 
 // KataloginformationAnvandarbehorighetEgna is ladok response from kataloginformation/anvandarbehorighet/egna
 type KataloginformationAnvandarbehorighetEgna struct {
 	Anvandarbehorighet []struct {
 		AnvandareRef struct {
-			Anvandarnamn string `json:"Anvandarnamn"`
-			Efternamn    string `json:"Efternamn"`
-			Fornamn      string `json:"Fornamn"`
-			UID          string `json:"Uid"`
-			Link         Link   `json:"link"`
-		} `json:"AnvandareRef"`
+			Anvandarnamn string `json:"Anvandarnamn" xml:"Anvandarnamn"`
+			Efternamn    string `json:"Efternamn" xml:"Efternamn"`
+			Fornamn      string `json:"Fornamn" xml:"Fornamn"`
+			UID          string `json:"Uid" xml:"Uid"`
+			Link         Link   `json:"link" xml:"link"`
+		} `json:"AnvandareRef" xml:"AnvandareRef"`
 		BehorighetsprofilRef struct {
-			Benamning []Benamning `json:"Benamning"`
-			UID       string      `json:"Uid"`
-			Link      Link        `json:"link"`
-		} `json:"BehorighetsprofilRef"`
-		BestalldTidpunkt string `json:"BestalldTidpunkt"`
-		LarosateID       int    `json:"LarosateID"`
+			Benamning []Benamning `json:"Benamning" xml:"Benamning"`
+			UID       string      `json:"Uid" xml:"Uid"`
+			Link      Link        `json:"link" xml:"link"`
+		} `json:"BehorighetsprofilRef" xml:"BehorighetsprofilRef"`
+		BestalldTidpunkt string `json:"BestalldTidpunkt" xml:"BestalldTidpunkt"`
+		LarosateID       int    `json:"LarosateID" xml:"LarosateID"`
 		OrganisationRef  struct {
-			Benamning []Benamning `json:"Benamning"`
-			UID       string      `json:"Uid"`
-			Link      Link        `json:"link"`
-		} `json:"OrganisationRef"`
-		SenastAndradAv string `json:"SenastAndradAv"`
-		SenastSparad   string `json:"SenastSparad"`
-		Status         string `json:"Status"`
-		UID            string `json:"Uid"`
-		Link           []Link `json:"link"`
-	} `json:"Anvandarbehorighet"`
-	LarosateID     int    `json:"LarosateID"`
-	SenastAndradAv string `json:"SenastAndradAv"`
-	SenastSparad   string `json:"SenastSparad"`
-	UID            string `json:"Uid"`
-	Link           []Link `json:"link"`
+			Benamning []Benamning `json:"Benamning" xml:"Benamning"`
+			UID       string      `json:"Uid" xml:"Uid"`
+			Link      Link        `json:"link" xml:"link"`
+		} `json:"OrganisationRef" xml:"OrganisationRef"`
+		SenastAndradAv string `json:"SenastAndradAv" xml:"SenastAndradAv"`
+		SenastSparad   string `json:"SenastSparad" xml:"SenastSparad"`
+		Status         string `json:"Status" xml:"Status"`
+		UID            string `json:"Uid" xml:"Uid"`
+		Link           []Link `json:"link" xml:"link"`
+	} `json:"Anvandarbehorighet" xml:"Anvandarbehorighet"`
+	LarosateID     int    `json:"LarosateID" xml:"LarosateID"`
+	SenastAndradAv string `json:"SenastAndradAv" xml:"SenastAndradAv"`
+	SenastSparad   string `json:"SenastSparad" xml:"SenastSparad"`
+	UID            string `json:"Uid" xml:"Uid"`
+	Link           []Link `json:"link" xml:"link"`
 }
 
 // Systemaktiviteter type
 type Systemaktiviteter struct {
-	Betafunktion      bool          `json:"Betafunktion"`
-	I18NNyckel        string        `json:"I18nNyckel"`
-	ID                int64         `json:"Id"`
-	KlarForProduktion bool          `json:"KlarForProduktion"`
-	Rattighetsniva    string        `json:"Rattighetsniva"`
-	Link              []interface{} `json:"link"`
+	Betafunktion      bool   `json:"Betafunktion" xml:"Betafunktion"`
+	I18NNyckel        string `json:"I18nNyckel" xml:"I18nNyckel"`
+	ID                int64  `json:"Id" xml:"Id"`
+	KlarForProduktion bool   `json:"KlarForProduktion" xml:"KlarForProduktion"`
+	Rattighetsniva    string `json:"Rattighetsniva" xml:"Rattighetsniva"`
+	Link              []Link `json:"link" xml:"link"`
+}
+
+// Dataavgransning limits a Behorighetsprofil to the data of DataId, like an organisation
+type Dataavgransning struct {
+	DataDimension  string `json:"DataDimension" xml:"DataDimension"`
+	DataID         string `json:"DataId" xml:"DataId"`
+	LarosateID     int    `json:"LarosateID" xml:"LarosateID"`
+	SenastAndradAv string `json:"SenastAndradAv" xml:"SenastAndradAv"`
+	SenastSparad   string `json:"SenastSparad" xml:"SenastSparad"`
+	UID            string `json:"Uid" xml:"Uid"`
+	Link           []Link `json:"link" xml:"link"`
 }
 
 // KataloginformationBehorighetsprofil type
 type KataloginformationBehorighetsprofil struct {
-	Benamning struct {
-		Sv string `json:"sv"`
-		En string `json:"en"`
-	} `json:"Benamning"`
+	Benamning         Benamningar `json:"Benamning" xml:"Benamning"`
 	Dataavgransningar struct {
-		Lista []interface{} `json:"Lista"`
-		Link  []interface{} `json:"link"`
-	} `json:"Dataavgransningar"`
-	LarosateID        int                 `json:"LarosateID"`
-	Rattighetsniva    string              `json:"Rattighetsniva"`
-	Systemaktiviteter []Systemaktiviteter `json:"Systemaktiviteter"`
-	UID               string              `json:"Uid"`
-	Link              []Link              `json:"link"`
+		Lista []Dataavgransning `json:"Lista" xml:"Lista"`
+		Link  []Link            `json:"link" xml:"link"`
+	} `json:"Dataavgransningar" xml:"Dataavgransningar"`
+	LarosateID        int                 `json:"LarosateID" xml:"LarosateID"`
+	Rattighetsniva    string              `json:"Rattighetsniva" xml:"Rattighetsniva"`
+	Systemaktiviteter []Systemaktiviteter `json:"Systemaktiviteter" xml:"Systemaktiviteter"`
+	UID               string              `json:"Uid" xml:"Uid"`
+	Link              []Link              `json:"link" xml:"link"`
 }
 
 // KataloginformationGrunddataLarosatesinformation ladok type
 type KataloginformationGrunddataLarosatesinformation struct {
-	LarosateID           int `json:"LarosateID"`
+	LarosateID           int `json:"LarosateID" xml:"LarosateID"`
 	Larosatesinformation []struct {
-		Benamning struct {
-			Sv string `json:"sv"`
-		} `json:"Benamning"`
-		Beskrivning struct {
-			Sv string `json:"sv"`
-		} `json:"Beskrivning"`
-		EpostadressForAdmingranssnitt   string `json:"EpostadressForAdmingranssnitt"`
-		EpostadressForStudentgranssnitt string `json:"EpostadressForStudentgranssnitt"`
+		Benamning                       Benamningar `json:"Benamning" xml:"Benamning"`
+		Beskrivning                     Benamningar `json:"Beskrivning" xml:"Beskrivning"`
+		EpostadressForAdmingranssnitt   string      `json:"EpostadressForAdmingranssnitt" xml:"EpostadressForAdmingranssnitt"`
+		EpostadressForStudentgranssnitt string      `json:"EpostadressForStudentgranssnitt" xml:"EpostadressForStudentgranssnitt"`
 		Giltighetsperiod                struct {
-			LarosateID int    `json:"LarosateID"`
-			Slutdatum  string `json:"Slutdatum"`
-			Startdatum string `json:"Startdatum"`
-			Link       []Link `json:"link"`
-		} `json:"Giltighetsperiod"`
-		ID                string `json:"ID"`
-		Kod               string `json:"Kod"`
+			LarosateID int    `json:"LarosateID" xml:"LarosateID"`
+			Slutdatum  string `json:"Slutdatum" xml:"Slutdatum"`
+			Startdatum string `json:"Startdatum" xml:"Startdatum"`
+			Link       []Link `json:"link" xml:"link"`
+		} `json:"Giltighetsperiod" xml:"Giltighetsperiod"`
+		ID                string `json:"ID" xml:"ID"`
+		Kod               string `json:"Kod" xml:"Kod"`
 		LankTillWebbplats struct {
-			Lanktext string `json:"Lanktext"`
-			URL      string `json:"Url"`
-		} `json:"LankTillWebbplats"`
+			Lanktext string `json:"Lanktext" xml:"Lanktext"`
+			URL      string `json:"Url" xml:"Url"`
+		} `json:"LankTillWebbplats" xml:"LankTillWebbplats"`
 		LankTillWebbplatsEngelskSida struct {
-			Lanktext string `json:"Lanktext"`
-			URL      string `json:"Url"`
-		} `json:"LankTillWebbplatsEngelskSida"`
-		LarosateID int `json:"LarosateID"`
-		OrtID      int `json:"OrtID"`
+			Lanktext string `json:"Lanktext" xml:"Lanktext"`
+			URL      string `json:"Url" xml:"Url"`
+		} `json:"LankTillWebbplatsEngelskSida" xml:"LankTillWebbplatsEngelskSida"`
+		LarosateID int `json:"LarosateID" xml:"LarosateID"`
+		OrtID      int `json:"OrtID" xml:"OrtID"`
 		Postadress struct {
-			Postnummer       string `json:"Postnummer"`
-			Postort          string `json:"Postort"`
-			Utdelningsadress string `json:"Utdelningsadress"`
-		} `json:"Postadress"`
-		Telefonnummer string `json:"Telefonnummer"`
-		Link          []Link `json:"link"`
-	} `json:"Larosatesinformation"`
-	Link []Link `json:"link"`
+			Postnummer       string `json:"Postnummer" xml:"Postnummer"`
+			Postort          string `json:"Postort" xml:"Postort"`
+			Utdelningsadress string `json:"Utdelningsadress" xml:"Utdelningsadress"`
+		} `json:"Postadress" xml:"Postadress"`
+		Telefonnummer string `json:"Telefonnummer" xml:"Telefonnummer"`
+		Link          []Link `json:"link" xml:"link"`
+	} `json:"Larosatesinformation" xml:"Larosatesinformation"`
+	Link []Link `json:"link" xml:"link"`
 }
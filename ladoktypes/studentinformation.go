@@ -1,31 +1,55 @@
 package ladoktypes
 
+import "encoding/xml"
+
 // Student is ladok reply from /studentinformation/student/{studentuid}
 type Student struct {
-	Avliden                           bool   `json:"Avliden"`
-	Efternamn                         string `json:"Efternamn"`
-	ExterntUID                        string `json:"ExterntUID"`
-	FelVidEtableringExternt           bool   `json:"FelVidEtableringExternt"`
-	Fodelsedata                       string `json:"Fodelsedata"`
-	FolkbokforingsbevakningTillOchMed string `json:"FolkbokforingsbevakningTillOchMed"`
-	Fornamn                           string `json:"Fornamn"`
-	KonID                             int    `json:"KonID"`
-	LarosateID                        int    `json:"LarosateID"`
-	Personnummer                      string `json:"Personnummer"`
-	SenastAndradAv                    string `json:"SenastAndradAv"`
-	SenastSparad                      string `json:"SenastSparad"`
-	UID                               string `json:"Uid"`
-	Link                              []Link `json:"link"`
+	Avliden                           bool   `json:"Avliden" xml:"Avliden"`
+	Efternamn                         string `json:"Efternamn" xml:"Efternamn"`
+	ExterntUID                        string `json:"ExterntUID" xml:"ExterntUID"`
+	FelVidEtableringExternt           bool   `json:"FelVidEtableringExternt" xml:"FelVidEtableringExternt"`
+	Fodelsedata                       string `json:"Fodelsedata" xml:"Fodelsedata"`
+	FolkbokforingsbevakningTillOchMed string `json:"FolkbokforingsbevakningTillOchMed" xml:"FolkbokforingsbevakningTillOchMed"`
+	Fornamn                           string `json:"Fornamn" xml:"Fornamn"`
+	KonID                             int    `json:"KonID" xml:"KonID"`
+	LarosateID                        int    `json:"LarosateID" xml:"LarosateID"`
+	Personnummer                      string `json:"Personnummer" xml:"Personnummer"`
+	SenastAndradAv                    string `json:"SenastAndradAv" xml:"SenastAndradAv"`
+	SenastSparad                      string `json:"SenastSparad" xml:"SenastSparad"`
+	UID                               string `json:"Uid" xml:"Uid"`
+	Link                              []Link `json:"link" xml:"link"`
+}
+
+// Kontaktuppgifter is ladok reply from, and request to, /studentinformation/student/{studentuid}/kontaktuppgifter
+type Kontaktuppgifter struct {
+	XMLName        xml.Name     `json:"-" xml:"http://schemas.ladok.se/studentinformation Kontaktuppgifter"`
+	Epostadress    string       `json:"Epostadress" xml:"Epostadress"`
+	Postadresser   []Postadress `json:"Postadresser" xml:"Postadresser"`
+	Telefonnummer  string       `json:"Telefonnummer" xml:"Telefonnummer"`
+	SenastAndradAv string       `json:"SenastAndradAv,omitempty" xml:"SenastAndradAv,omitempty"`
+	SenastSparad   string       `json:"SenastSparad,omitempty" xml:"SenastSparad,omitempty"`
+	UID            string       `json:"Uid,omitempty" xml:"Uid,omitempty"`
+	Link           []Link       `json:"link,omitempty" xml:"link,omitempty"`
+}
+
+// Postadress is a postal address of Kontaktuppgifter
+type Postadress struct {
+	CareOf           string `json:"CareOf" xml:"CareOf"`
+	Land             string `json:"Land" xml:"Land"`
+	PostadressTyp    string `json:"PostadressTyp" xml:"PostadressTyp"`
+	Postnummer       string `json:"Postnummer" xml:"Postnummer"`
+	Postort          string `json:"Postort" xml:"Postort"`
+	Utdelningsadress string `json:"Utdelningsadress" xml:"Utdelningsadress"`
 }
 
 // AktivPaLarosate is ladok reply from /studentinformation/student/{uid}/aktivpalarosate
 type AktivPaLarosate struct {
 	Studentkopplingar []struct {
-		LarosateID int    `json:"larosateID"`
-		Link       []Link `json:"link"`
-		StudentUID string `json:"studentUID"`
-	} `json:"Studentkopplingar"`
-	Link []Link `json:"link"`
+		LarosateID int    `json:"larosateID" xml:"LarosateID"`
+		Link       []Link `json:"link" xml:"link"`
+		StudentUID string `json:"studentUID" xml:"StudentUID"`
+	} `json:"Studentkopplingar" xml:"Studentkopplingar"`
+	Link []Link `json:"link" xml:"link"`
 }
 
 // GenderString translate from KonID to the equal string value
@@ -0,0 +1,90 @@
+package goladok3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/masv3971/goladok3/ladoktypes"
+)
+
+var (
+	// ErrLinkNotFound if no link has the requested relation
+	ErrLinkNotFound = errors.New("No link found with relation")
+	// ErrLinkOutsideBaseURL if a link points to another scheme, host or port than the client URL, the client certificate
+	// or token is only sent to ladok
+	ErrLinkOutsideBaseURL = errors.New("Link points outside the ladok URL")
+)
+
+// Follow requests the resource of link with its method, decoding the reply into out, if not nil.
+// Relative links are resolved against the client URL, absolute links have to have the same scheme, host and port.
+func (c *Client) Follow(ctx context.Context, link ladoktypes.Link, out interface{}) (*http.Response, error) {
+	target, err := url.Parse(link.URI)
+	if err != nil {
+		return nil, err
+	}
+	if target.IsAbs() {
+		base, err := url.Parse(c.url)
+		if err != nil {
+			return nil, err
+		}
+		if !sameOrigin(target, base) {
+			return nil, fmt.Errorf("%w: %s://%s", ErrLinkOutsideBaseURL, target.Scheme, target.Host)
+		}
+	}
+
+	service := strings.SplitN(strings.TrimPrefix(target.Path, "/"), "/", 2)[0]
+
+	// Ladok links carry the generic application/vnd.ladok+xml whatever the reply format, so the media type
+	// of the service in the client format is used, and the link media type only for services unknown here
+	accept, err := c.serviceAcceptHeader(service)
+	if err != nil {
+		if link.MediaType == "" {
+			return nil, err
+		}
+		accept = c.linkMediaType(link.MediaType)
+	}
+
+	method := strings.ToUpper(link.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	return c.call(ctx, Endpoint{Service: serviceKey(service), Operation: "Follow"}, accept, method, link.URI, nil, out)
+}
+
+// FollowRel follows the link with relation rel among links, see ladoktypes.FindLink
+func (c *Client) FollowRel(ctx context.Context, links []ladoktypes.Link, rel string, out interface{}) (*http.Response, error) {
+	link, ok := ladoktypes.FindLink(links, rel)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrLinkNotFound, rel)
+	}
+	return c.Follow(ctx, link, out)
+}
+
+// sameOrigin reports if a and b have the same scheme, host and port, a missing port is the default port of the scheme
+func sameOrigin(a, b *url.URL) bool {
+	port := func(u *url.URL) string {
+		if p := u.Port(); p != "" {
+			return p
+		}
+		return defaultPorts[strings.ToLower(u.Scheme)]
+	}
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Hostname(), b.Hostname()) && port(a) == port(b)
+}
+
+// linkMediaType returns the media type of mediaTypes, a comma separated list as in ladok links, in the client
+// format, or the first one
+func (c *Client) linkMediaType(mediaTypes string) string {
+	list := strings.Split(mediaTypes, ",")
+	for _, mediaType := range list {
+		mediaType = strings.TrimSpace(mediaType)
+		if strings.HasSuffix(mediaType, "+"+c.format) {
+			return mediaType
+		}
+	}
+	return strings.TrimSpace(list[0])
+}
@@ -0,0 +1,170 @@
+package goladok3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollow(t *testing.T) {
+	uid := ladokmocks.Students[0].StudentUID
+
+	var gotAccept, gotMethod string
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/studentinformation/student/"+uid, func(w http.ResponseWriter, r *http.Request) {
+		gotAccept = r.Header.Get("Accept")
+		gotMethod = r.Method
+		if gotAccept == "application/vnd.ladok-studentinformation+json" {
+			w.Header().Set("Content-Type", ContentTypeStudentinformationJSON)
+			w.Write(ladokmocks.JSONStudentinformationStudent)
+			return
+		}
+		w.Header().Set("Content-Type", ContentTypeStudentinformationXML)
+		w.Write(ladokmocks.XMLStudentinformationStudent)
+	})
+	mux.HandleFunc("/studiedeltagande/student/"+uid, func(w http.ResponseWriter, r *http.Request) {
+		gotAccept = r.Header.Get("Accept")
+		gotMethod = r.Method
+		w.Header().Set("Content-Type", ContentTypeStudiedeltagandeJSON)
+		w.Write(ladokmocks.JSONStudentinformationStudent)
+	})
+	mux.HandleFunc("/okand/student/"+uid, func(w http.ResponseWriter, r *http.Request) {
+		gotAccept = r.Header.Get("Accept")
+		gotMethod = r.Method
+		w.Header().Set("Content-Type", ContentTypeStudentinformationJSON)
+		w.Write(ladokmocks.JSONStudentinformationStudent)
+	})
+	serverURL, _ := url.Parse(server.URL)
+
+	links := []ladoktypes.Link{
+		{
+			Method:    "GET",
+			URI:       server.URL + "/studentinformation/student/" + uid,
+			MediaType: "application/vnd.ladok+xml",
+			Rel:       "http://relations.ladok.se/studentinformation/student",
+		},
+		{
+			Method: "get",
+			URI:    "/studentinformation/student/" + uid,
+			Rel:    "self",
+		},
+		{
+			Method: "GET",
+			URI:    "https://ladok.example.com/studentinformation/student/" + uid,
+			Rel:    "elsewhere",
+		},
+		{
+			Method: "GET",
+			URI:    "https://" + serverURL.Host + "/studentinformation/student/" + uid,
+			Rel:    "other scheme",
+		},
+		{
+			Method: "GET",
+			URI:    "http://" + serverURL.Hostname() + ":1/studentinformation/student/" + uid,
+			Rel:    "other port",
+		},
+		{
+			Method:    "GET",
+			URI:       server.URL + "/studiedeltagande/student/" + uid,
+			MediaType: "application/vnd.ladok+xml",
+			Rel:       "studiedeltagande",
+		},
+		{
+			Method:    "GET",
+			URI:       server.URL + "/okand/student/" + uid,
+			MediaType: "application/vnd.ladok+xml,application/vnd.ladok-okand+xml,application/vnd.ladok-okand+json",
+			Rel:       "okand",
+		},
+	}
+
+	tts := []struct {
+		name       string
+		rel        string
+		format     string
+		wantAccept string
+		wantErr    error
+	}{
+		{
+			name:       "absolute link with generic media type",
+			rel:        "student",
+			format:     FormatJSON,
+			wantAccept: "application/vnd.ladok-studentinformation+json",
+		},
+		{
+			name:       "absolute link in xml format",
+			rel:        "student",
+			format:     FormatXML,
+			wantAccept: "application/vnd.ladok-studentinformation+xml",
+		},
+		{
+			name:       "relative link without media type",
+			rel:        "self",
+			format:     FormatJSON,
+			wantAccept: "application/vnd.ladok-studentinformation+json",
+		},
+		{
+			name:    "other host",
+			rel:     "elsewhere",
+			wantErr: ErrLinkOutsideBaseURL,
+		},
+		{
+			name:    "other scheme",
+			rel:     "other scheme",
+			wantErr: ErrLinkOutsideBaseURL,
+		},
+		{
+			name:    "other port",
+			rel:     "other port",
+			wantErr: ErrLinkOutsideBaseURL,
+		},
+		{
+			name:       "service with another path",
+			rel:        "studiedeltagande",
+			format:     FormatJSON,
+			wantAccept: "application/vnd.ladok-studiedeltagande+json",
+		},
+		{
+			name:       "unknown service with media type list",
+			rel:        "okand",
+			format:     FormatJSON,
+			wantAccept: "application/vnd.ladok-okand+json",
+		},
+		{
+			name:    "no link",
+			rel:     "kurs",
+			wantErr: ErrLinkNotFound,
+		},
+	}
+
+	client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL)
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAccept, gotMethod = "", ""
+			if tt.format != "" {
+				client.format = tt.format
+			}
+			student := &ladoktypes.Student{}
+			_, err := client.FollowRel(context.TODO(), links, tt.rel, student)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, gotMethod)
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, tt.wantAccept, gotAccept)
+			assert.Equal(t, http.MethodGet, gotMethod)
+			assert.Equal(t, "11111111-2222-0000-0000-000000000000", student.UID)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package goladok3
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Logger receives the request log of the client, it is satisfied by *slog.Logger
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+}
+
+// LogLevel sets how much of the ladok traffic is logged
+type LogLevel int
+
+const (
+	// LogRequests logs method, URL, status and duration of every request
+	LogRequests LogLevel = iota
+	// LogBodies logs the request and response bodies as well
+	LogBodies
+)
+
+// withLogging returns middleware with the request logger as the innermost middleware, so each attempt is logged
+func withLogging(middleware []Middleware, logger Logger, level LogLevel) []Middleware {
+	if logger == nil {
+		return middleware
+	}
+	return append(append([]Middleware{}, middleware...), loggingMiddleware(logger, level))
+}
+
+// loggingMiddleware logs requests to logger, personal data in URLs, bodies and errors is redacted
+func loggingMiddleware(logger Logger, level LogLevel) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			endpoint, _ := EndpointFromContext(ctx)
+			args := []interface{}{
+				"service", endpoint.Service,
+				"operation", endpoint.Operation,
+				"method", req.Method,
+				"url", Redact(req.URL.String()),
+			}
+
+			if level >= LogBodies && req.GetBody != nil {
+				if body, err := req.GetBody(); err == nil {
+					b, _ := io.ReadAll(body)
+					body.Close()
+					args = append(args, "request_body", Redact(string(b)))
+				}
+			}
+
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			args = append(args, "duration", time.Since(start))
+			if err != nil {
+				logger.WarnContext(ctx, "ladok request failed", append(args, "error", Redact(err.Error()))...)
+				return resp, err
+			}
+
+			args = append(args, "status", resp.StatusCode, "content_type", resp.Header.Get("Content-Type"))
+			if level >= LogBodies {
+				b, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				resp.Body = io.NopCloser(bytes.NewReader(b))
+				if err != nil {
+					return resp, err
+				}
+				args = append(args, "response_body", Redact(string(b)))
+			}
+
+			logger.DebugContext(ctx, "ladok request", args...)
+			return resp, nil
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package goladok3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogging(t *testing.T) {
+	personnummer := ladokmocks.Students[0].Personnummer
+
+	tts := []struct {
+		name     string
+		level    LogLevel
+		wantBody bool
+	}{
+		{
+			name:  "requests",
+			level: LogRequests,
+		},
+		{
+			name:     "bodies",
+			level:    LogBodies,
+			wantBody: true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			defer server.Close()
+			mockGenericEndpointServer(t, mux, ContentTypeStudentinformationJSON, "GET", "/studentinformation/student/personnummer/"+personnummer, ladokmocks.JSONStudentinformationStudent, 200)
+
+			buf := &bytes.Buffer{}
+			client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL, func(cfg *X509Config) {
+				cfg.Logger = slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+				cfg.LogLevel = tt.level
+			})
+
+			student, _, err := client.Studentinformation.GetStudent(context.TODO(), &GetStudentReq{Personnummer: personnummer})
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			// The reply is still decoded after the body has been logged
+			assert.NotEmpty(t, student.Personnummer)
+
+			assert.NotContains(t, buf.String(), personnummer)
+			assert.NotContains(t, buf.String(), student.Personnummer)
+
+			record := map[string]interface{}{}
+			if !assert.NoError(t, json.Unmarshal(buf.Bytes(), &record)) {
+				t.FailNow()
+			}
+			assert.Equal(t, "DEBUG", record["level"])
+			assert.Equal(t, "studentinformation", record["service"])
+			assert.Equal(t, "GetStudent", record["operation"])
+			assert.Equal(t, server.URL+"/studentinformation/student/personnummer/[REDACTED]", record["url"])
+			assert.Equal(t, float64(200), record["status"])
+
+			body, ok := record["response_body"].(string)
+			assert.Equal(t, tt.wantBody, ok)
+			if tt.wantBody {
+				assert.True(t, strings.Contains(body, `"Personnummer": "[REDACTED]"`), body)
+				assert.True(t, json.Valid([]byte(body)), body)
+			}
+		})
+	}
+}
+
+func TestLoggingError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	client := mockNewClient(t, ladoktypes.EnvProdAPI, "http://127.0.0.1:1", func(cfg *X509Config) {
+		cfg.Logger = slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	})
+
+	personnummer := ladokmocks.Students[0].Personnummer
+	_, _, err := client.Studentinformation.GetStudent(context.TODO(), &GetStudentReq{Personnummer: personnummer})
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "level=WARN")
+	assert.Contains(t, buf.String(), "ladok request failed")
+	assert.NotContains(t, buf.String(), personnummer)
+}
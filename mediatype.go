@@ -0,0 +1,147 @@
+package goladok3
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+
+	"github.com/masv3971/goladok3/ladoktypes"
+)
+
+// DecodeFunc decodes a response body into v
+type DecodeFunc func(r io.Reader, v interface{}) error
+
+func decodeJSON(r io.Reader, v interface{}) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+func decodeXML(r io.Reader, v interface{}) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+// encodeBody encodes body for a request of mediaType, as xml for xml media types and as json otherwise.
+// A []byte body is sent as it is.
+func encodeBody(mediaType string, body interface{}) (*bytes.Reader, error) {
+	if b, ok := body.([]byte); ok {
+		return bytes.NewReader(b), nil
+	}
+
+	parsed, _, _ := mime.ParseMediaType(mediaType)
+	if strings.HasSuffix(parsed, "xml") {
+		b, err := xml.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(append([]byte(xml.Header), b...)), nil
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// mediaTypes keeps decoders keyed by media type and by structured syntax suffix, like +json
+type mediaTypes struct {
+	mu       sync.RWMutex
+	types    map[string]DecodeFunc
+	suffixes map[string]DecodeFunc
+}
+
+// newMediaTypes returns a registry covering every service in ladokAcceptHeader
+func newMediaTypes() *mediaTypes {
+	m := &mediaTypes{
+		types: map[string]DecodeFunc{
+			"application/json":     decodeJSON,
+			"application/xml":      decodeXML,
+			"text/xml":             decodeXML,
+			"application/atom+xml": decodeXML,
+		},
+		suffixes: map[string]DecodeFunc{
+			"+json": decodeJSON,
+			"+xml":  decodeXML,
+		},
+	}
+
+	for _, formats := range ladokAcceptHeader {
+		for format, mediaType := range formats {
+			switch format {
+			case FormatJSON:
+				m.types[mediaType] = decodeJSON
+			case FormatXML:
+				m.types[mediaType] = decodeXML
+			}
+		}
+	}
+
+	return m
+}
+
+// clone returns a copy of m that can be registered to without changing m
+func (m *mediaTypes) clone() *mediaTypes {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	clone := &mediaTypes{
+		types:    make(map[string]DecodeFunc, len(m.types)),
+		suffixes: make(map[string]DecodeFunc, len(m.suffixes)),
+	}
+	for mediaType, decode := range m.types {
+		clone.types[mediaType] = decode
+	}
+	for suffix, decode := range m.suffixes {
+		clone.suffixes[suffix] = decode
+	}
+	return clone
+}
+
+// register adds decode for mediaType, a mediaType starting with "+" is registered as a suffix
+func (m *mediaTypes) register(mediaType string, decode DecodeFunc) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if strings.HasPrefix(mediaType, "+") && len(mediaType) > 1 {
+		m.suffixes[strings.ToLower(mediaType)] = decode
+		return nil
+	}
+
+	parsed, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return err
+	}
+	m.types[parsed] = decode
+	return nil
+}
+
+// decoder returns the decoder for contentType, exact media types are preferred over suffixes
+func (m *mediaTypes) decoder(contentType string) (DecodeFunc, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, ladoktypes.ErrNoValidContentType
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if decode, ok := m.types[mediaType]; ok {
+		return decode, nil
+	}
+	if i := strings.LastIndex(mediaType, "+"); i != -1 {
+		if decode, ok := m.suffixes[mediaType[i:]]; ok {
+			return decode, nil
+		}
+	}
+
+	return nil, ladoktypes.ErrNoValidContentType
+}
+
+// RegisterMediaType makes the client decode responses of mediaType with decode.
+// A mediaType starting with "+", like "+cbor", registers a structured syntax suffix.
+func (c *Client) RegisterMediaType(mediaType string, decode DecodeFunc) error {
+	return c.mediaTypes.register(mediaType, decode)
+}
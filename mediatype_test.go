@@ -0,0 +1,112 @@
+package goladok3
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaTypesDecoder(t *testing.T) {
+	tts := []struct {
+		name        string
+		contentType string
+		wantErr     error
+	}{
+		{
+			name:        "kataloginformation json",
+			contentType: ContentTypeKataloginformationJSON,
+		},
+		{
+			name:        "lower case charset",
+			contentType: "application/vnd.ladok-studentinformation+json;charset=utf-8",
+		},
+		{
+			name:        "space before parameter",
+			contentType: "application/vnd.ladok-studiedeltagande+json; charset=UTF-8",
+		},
+		{
+			name:        "resultat json",
+			contentType: "application/vnd.ladok-resultat+json",
+		},
+		{
+			name:        "examen xml",
+			contentType: "application/vnd.ladok-examen+xml;charset=UTF-8",
+		},
+		{
+			name:        "utbildningsinformation json",
+			contentType: "application/vnd.ladok-utbildningsinformation+json",
+		},
+		{
+			name:        "atom",
+			contentType: ContentTypeAtomXML,
+		},
+		{
+			name:        "unknown vendor type with known suffix",
+			contentType: "application/vnd.ladok-nyservice+json",
+		},
+		{
+			name:        "upper case",
+			contentType: "Application/VND.Ladok-Kataloginformation+JSON",
+		},
+		{
+			name:        "html",
+			contentType: "text/html",
+			wantErr:     ladoktypes.ErrNoValidContentType,
+		},
+		{
+			name:        "empty",
+			contentType: "",
+			wantErr:     ladoktypes.ErrNoValidContentType,
+		},
+	}
+
+	m := newMediaTypes()
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			decode, err := m.decoder(tt.contentType)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, decode)
+		})
+	}
+}
+
+func TestRegisterMediaType(t *testing.T) {
+	mux, server, client := mockSetup(t, ladoktypes.EnvProdAPI)
+	defer server.Close()
+
+	decodeText := func(r io.Reader, v interface{}) error {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		v.(*ladoktypes.Student).Fornamn = strings.TrimSpace(string(b))
+		return nil
+	}
+
+	assert.Error(t, client.RegisterMediaType("not a media type;", decodeText))
+	if !assert.NoError(t, client.RegisterMediaType("text/plain", decodeText)) {
+		t.FailNow()
+	}
+
+	url := "/studentinformation/student/" + ladokmocks.Students[0].StudentUID
+	mux.HandleFunc(url, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("TestFornamn\n"))
+	})
+
+	student, _, err := client.Studentinformation.GetStudent(context.TODO(), &GetStudentReq{UID: ladokmocks.Students[0].StudentUID})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "TestFornamn", student.Fornamn)
+}
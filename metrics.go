@@ -0,0 +1,27 @@
+package goladok3
+
+import "time"
+
+// Metrics receives the request, rate limiting and feed metrics of the client,
+// ladokmetrics.New returns one backed by prometheus
+type Metrics interface {
+	// ObserveRequest records a finished call to operation of service, status is 0 if no response was received
+	ObserveRequest(service, operation string, status int, duration time.Duration)
+	// ObserveRateLimitWait records the time waited for the rate limiter before an attempt
+	ObserveRateLimitWait(wait time.Duration)
+	// IncRetries counts a retried attempt of operation of service
+	IncRetries(service, operation string)
+	// IncDecodeFailures counts a successful reply of operation of service that could not be decoded or parsed
+	IncDecodeFailures(service, operation string)
+	// ObserveFeed records the id of a feed read by operation, Recent, First or Historical
+	ObserveFeed(operation string, id int)
+}
+
+// noMetrics records nothing, it is used when no Metrics is set
+type noMetrics struct{}
+
+func (noMetrics) ObserveRequest(string, string, int, time.Duration) {}
+func (noMetrics) ObserveRateLimitWait(time.Duration)                {}
+func (noMetrics) IncRetries(string, string)                         {}
+func (noMetrics) IncDecodeFailures(string, string)                  {}
+func (noMetrics) ObserveFeed(string, int)                           {}
@@ -0,0 +1,144 @@
+package goladok3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockMetrics counts the calls made by the client
+type mockMetrics struct {
+	mu             sync.Mutex
+	requests       map[string]int
+	rateLimitWaits int
+	retries        map[string]int
+	decodeFailures map[string]int
+	feeds          []string
+}
+
+func newMockMetrics() *mockMetrics {
+	return &mockMetrics{
+		requests:       map[string]int{},
+		retries:        map[string]int{},
+		decodeFailures: map[string]int{},
+	}
+}
+
+func (m *mockMetrics) ObserveRequest(service, operation string, status int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[service+"/"+operation+"/"+strconv.Itoa(status)]++
+}
+
+func (m *mockMetrics) ObserveRateLimitWait(wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rateLimitWaits++
+}
+
+func (m *mockMetrics) IncRetries(service, operation string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries[service+"/"+operation]++
+}
+
+func (m *mockMetrics) IncDecodeFailures(service, operation string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.decodeFailures[service+"/"+operation]++
+}
+
+func (m *mockMetrics) ObserveFeed(operation string, id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.feeds = append(m.feeds, operation+"/"+strconv.Itoa(id))
+}
+
+func TestMetrics(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	hits := 0
+	mux.HandleFunc("/kataloginformation/anvandare/autentiserad", func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+		w.Write(ladokmocks.JSONKataloginformationAutentiserad)
+	})
+	mux.HandleFunc("/kataloginformation/anvandarbehorighet/egna", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+		w.Write([]byte("{broken"))
+	})
+	mockGenericEndpointServer(t, mux, ContentTypeStudentinformationJSON, "GET", "/studentinformation/student/"+ladokmocks.Students[0].StudentUID, ladokmocks.JSONErrors500, 500)
+
+	metrics := newMockMetrics()
+	client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL, func(cfg *X509Config) {
+		cfg.RetryPolicy = mockRetryPolicy()
+		cfg.Metrics = metrics
+	})
+
+	_, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.NoError(t, err)
+	_, _, err = client.Kataloginformation.GetAnvandarbehorighetEgna(context.TODO())
+	assert.Error(t, err)
+	_, _, err = client.Studentinformation.GetStudent(context.TODO(), &GetStudentReq{UID: ladokmocks.Students[0].StudentUID})
+	assert.Error(t, err)
+
+	assert.Equal(t, map[string]int{
+		"kataloginformation/GetAnvandareAutentiserad/200":  1,
+		"kataloginformation/GetAnvandarbehorighetEgna/200": 1,
+		"studentinformation/GetStudent/500":                1,
+	}, metrics.requests)
+	assert.Equal(t, map[string]int{"kataloginformation/GetAnvandareAutentiserad": 1}, metrics.retries)
+	assert.Equal(t, map[string]int{"kataloginformation/GetAnvandarbehorighetEgna": 1}, metrics.decodeFailures)
+
+	// One rate limiter wait per attempt
+	assert.Equal(t, 4, metrics.rateLimitWaits)
+}
+
+func TestMetricsFeed(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mockGenericEndpointServer(t, mux, ContentTypeAtomXML, "GET", "/uppfoljning/feed/recent", ladokmocks.XMLFeedRecent, 200)
+	mockGenericEndpointServer(t, mux, ContentTypeAtomXML, "GET", "/uppfoljning/feed/100", ladokmocks.XMLFeedRecent, 200)
+
+	metrics := newMockMetrics()
+	client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL, func(cfg *X509Config) {
+		cfg.RetryPolicy = mockRetryPolicy()
+		cfg.Metrics = metrics
+	})
+
+	recent, _, err := client.Feed.Recent(context.TODO())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	_, _, err = client.Feed.Historical(context.TODO(), &HistoricalReq{ID: 100})
+	assert.NoError(t, err)
+
+	id := strconv.Itoa(recent.ID)
+	assert.Equal(t, []string{"Recent/" + id, "Historical/" + id}, metrics.feeds)
+}
+
+func TestMetricsNotSet(t *testing.T) {
+	mux, server, client := mockSetup(t, ladoktypes.EnvProdAPI)
+	defer server.Close()
+
+	mockGenericEndpointServer(t, mux, ContentTypeAtomXML, "GET", "/uppfoljning/feed/recent", ladokmocks.XMLFeedRecent, 200)
+
+	_, _, err := client.Feed.Recent(context.TODO())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,51 @@
+package goladok3
+
+import (
+	"context"
+	"net/http"
+)
+
+// Endpoint describes the ladok endpoint a request is made to
+type Endpoint struct {
+	// Service is the ladok service, like studentinformation or feed
+	Service string
+	// Operation is the client method making the request, like GetStudent
+	Operation string
+}
+
+type endpointKey struct{}
+
+// withEndpoint returns ctx carrying endpoint
+func withEndpoint(ctx context.Context, endpoint Endpoint) context.Context {
+	return context.WithValue(ctx, endpointKey{}, endpoint)
+}
+
+// EndpointFromContext returns the Endpoint of a request made by the client, use req.Context() in a Middleware
+func EndpointFromContext(ctx context.Context) (Endpoint, bool) {
+	endpoint, ok := ctx.Value(endpointKey{}).(Endpoint)
+	return endpoint, ok
+}
+
+// RoundTripperFunc is a function used as http.RoundTripper
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the round trip of every request to ladok, including each retry.
+// The Endpoint called is found by EndpointFromContext(req.Context()).
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// chainMiddleware returns the RoundTripper sending requests through middleware and then the HTTPClient,
+// the first middleware is the outermost
+func (c *Client) chainMiddleware(middleware []Middleware) http.RoundTripper {
+	var next http.RoundTripper = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return c.HTTPClient.Do(req)
+	})
+	for i := len(middleware) - 1; i >= 0; i-- {
+		next = middleware[i](next)
+	}
+	return next
+}
@@ -0,0 +1,114 @@
+package goladok3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mockGenericEndpointServer(t, mux, ContentTypeKataloginformationJSON, "GET", "/kataloginformation/anvandare/autentiserad", ladokmocks.JSONKataloginformationAutentiserad, 200)
+	mockGenericEndpointServer(t, mux, ContentTypeStudentinformationJSON, "GET", fmt.Sprintf("/studentinformation/student/%s", ladokmocks.Students[0].StudentUID), ladokmocks.JSONStudentinformationStudent, 200)
+
+	var (
+		mu        sync.Mutex
+		order     []string
+		endpoints []Endpoint
+		headers   []string
+	)
+	named := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				order = append(order, name)
+				mu.Unlock()
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	recordEndpoint := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Test", "middleware")
+			endpoint, ok := EndpointFromContext(req.Context())
+			assert.True(t, ok)
+
+			resp, err := next.RoundTrip(req)
+
+			mu.Lock()
+			endpoints = append(endpoints, endpoint)
+			mu.Unlock()
+			return resp, err
+		})
+	}
+	mux.HandleFunc("/kataloginformation/anvandarbehorighet/egna", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Get("X-Test"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+		w.Write(ladokmocks.JSONKataloginformationEgna)
+	})
+
+	client := mockNewClient(t, ladoktypes.EnvIntTestAPI, server.URL, func(cfg *X509Config) {
+		cfg.Middleware = []Middleware{named("outer"), named("inner"), recordEndpoint}
+	})
+
+	_, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.NoError(t, err)
+	_, _, err = client.Studentinformation.GetStudent(context.TODO(), &GetStudentReq{UID: ladokmocks.Students[0].StudentUID})
+	assert.NoError(t, err)
+	_, _, err = client.Kataloginformation.GetAnvandarbehorighetEgna(context.TODO())
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"outer", "inner", "outer", "inner", "outer", "inner"}, order)
+	assert.Equal(t, []Endpoint{
+		{Service: "kataloginformation", Operation: "GetAnvandareAutentiserad"},
+		{Service: "studentinformation", Operation: "GetStudent"},
+		{Service: "kataloginformation", Operation: "GetAnvandarbehorighetEgna"},
+	}, endpoints)
+	assert.Equal(t, []string{"middleware"}, headers)
+}
+
+func TestMiddlewareRetries(t *testing.T) {
+	mux, server, _ := mockSetup(t, ladoktypes.EnvProdAPI)
+	defer server.Close()
+
+	hits := 0
+	mux.HandleFunc("/kataloginformation/anvandare/autentiserad", func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits < 3 {
+			w.WriteHeader(503)
+			return
+		}
+		w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+		w.Write(ladokmocks.JSONKataloginformationAutentiserad)
+	})
+
+	statuses := []int{}
+	client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL, func(cfg *X509Config) {
+		cfg.RetryPolicy = mockRetryPolicy()
+		cfg.Middleware = []Middleware{func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				resp, err := next.RoundTrip(req)
+				if err == nil {
+					statuses = append(statuses, resp.StatusCode)
+				}
+				return resp, err
+			})
+		}}
+	})
+
+	_, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{503, 503, 200}, statuses)
+}
@@ -0,0 +1,412 @@
+package goladok3
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+var (
+	// ErrNoTokenEndpoint if the issuer does not publish a token endpoint
+	ErrNoTokenEndpoint = errors.New("No token endpoint found for issuer")
+	// ErrUnsupportedKey if the private key is not RSA or ECDSA P-256
+	ErrUnsupportedKey = errors.New("Unsupported private key type")
+)
+
+// tokenEarlyExpiry is how long before expiry a token is refreshed
+var tokenEarlyExpiry = time.Minute
+
+// OidcConfig configures NewOIDC function.
+// Either TokenSource, typically holding the token of a user authenticated via OIDC,
+// or ClientID together with ClientSecret or PrivateKeyPEM (private_key_jwt) has to be set.
+type OidcConfig struct {
+	// URL is the ladok api base URL, when empty it is chosen by Environment, see DefaultBaseURLs
+	URL string `validate:"required_without=Environment"`
+	// BaseURLs overrides DefaultBaseURLs for this client
+	BaseURLs map[string]string
+	// Issuer is used for discovery of the token endpoint, not needed if TokenURL is set
+	Issuer string `validate:"required_without_all=TokenURL TokenSource"`
+	// TokenURL overrides the token endpoint found by discovery
+	TokenURL     string
+	ClientID     string `validate:"required_without=TokenSource"`
+	ClientSecret string `validate:"required_without_all=PrivateKeyPEM TokenSource"`
+	// PrivateKeyPEM is a RSA or ECDSA P-256 key used to sign the client assertion
+	PrivateKeyPEM []byte
+	// KeyID is set as kid in the client assertion header
+	KeyID  string
+	Scopes []string
+	// TokenSource provides the bearer token, when set the client credentials fields are not used.
+	// It has to refresh tokens itself, a 401 is only retried if it returns a new token when asked again.
+	TokenSource oauth2.TokenSource
+	// Environment is the ladok environment, ladoktypes.EnvIntTestAPI, EnvTestAPI or EnvProdAPI, used by Feed
+	Environment string `validate:"omitempty,oneof=Int-test-API Test-API Prod-API"`
+	// TLS configures versions and cipher suites, the defaults allow TLS 1.2 and 1.3
+	TLS TLSOptions
+	// Transport configures timeouts, connection pooling and HTTP/2
+	Transport TransportOptions
+	// ProxyURL routes ladok and token traffic through this proxy, see X509Config.ProxyURL
+	ProxyURL string
+	// NoProxy lists hosts not to reach through ProxyURL, see X509Config.NoProxy
+	NoProxy []string
+	// Format is the representation requested from ladok, FormatJSON (default) or FormatXML
+	Format string `validate:"omitempty,oneof=json xml"`
+	// RetryPolicy enables retries of idempotent requests, nil means a single attempt
+	RetryPolicy *RetryPolicy
+	// Middleware wraps every request to ladok, the first one is the outermost
+	Middleware []Middleware
+	// TracerProvider enables OpenTelemetry spans, see X509Config.TracerProvider
+	TracerProvider trace.TracerProvider
+	// Propagator injects the trace context into requests, see X509Config.Propagator
+	Propagator propagation.TextMapPropagator
+	// Metrics records request, rate limiting and feed metrics, see X509Config.Metrics
+	Metrics Metrics
+	// Logger logs every request to ladok with personal data redacted, see Redact. Use a *slog.Logger at debug level.
+	Logger Logger
+	// LogLevel is LogRequests (default) or LogBodies
+	LogLevel LogLevel `validate:"omitempty,oneof=0 1"`
+	// Cache enables caching of GET responses, nil disables it
+	Cache *CacheConfig
+}
+
+// NewOIDC create a new OIDC instance of ladok, like New(config)
+func NewOIDC(config OidcConfig) (*Client, error) {
+	return New(config)
+}
+
+// settings returns the settings of config shared by all authentication methods
+func (config OidcConfig) settings() clientSettings {
+	return clientSettings{
+		URL:            config.URL,
+		BaseURLs:       config.BaseURLs,
+		Environment:    config.Environment,
+		Format:         config.Format,
+		ProxyURL:       config.ProxyURL,
+		RetryPolicy:    config.RetryPolicy,
+		Middleware:     config.Middleware,
+		TracerProvider: config.TracerProvider,
+		Propagator:     config.Propagator,
+		Metrics:        config.Metrics,
+		Logger:         config.Logger,
+		LogLevel:       config.LogLevel,
+		Cache:          config.Cache,
+	}
+}
+
+// authenticate sets up a transport adding bearer tokens from the OIDC provider of config
+func (config OidcConfig) authenticate(c *Client) error {
+	tlsCfg, err := newTLSConfig(config.TLS)
+	if err != nil {
+		return err
+	}
+	proxy, err := proxyFunc(config.ProxyURL, config.NoProxy)
+	if err != nil {
+		return err
+	}
+	base := newTransport(tlsCfg, config.Transport, proxy)
+
+	source := config.TokenSource
+	if source == nil {
+		var signer crypto.Signer
+		if config.PrivateKeyPEM != nil {
+			signer, err = parseSigner(config.PrivateKeyPEM)
+			if err != nil {
+				return err
+			}
+		}
+		source = &clientCredentialsSource{
+			config:     config,
+			signer:     signer,
+			httpClient: &http.Client{Transport: base, Timeout: 30 * time.Second},
+		}
+	}
+
+	c.HTTPClient = &http.Client{
+		Transport: &bearerTransport{
+			base:   base,
+			tokens: &tokenCache{source: source},
+		},
+	}
+
+	return nil
+}
+
+// tokenCache caches the token of source until shortly before it expires
+type tokenCache struct {
+	mu     sync.Mutex
+	source oauth2.TokenSource
+	token  *oauth2.Token
+}
+
+// get returns a valid token, fetching a new one if the cached one expires within tokenEarlyExpiry or force is set
+func (t *tokenCache) get(force bool) (*oauth2.Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !force && t.token != nil && t.token.AccessToken != "" &&
+		(t.token.Expiry.IsZero() || time.Until(t.token.Expiry) > tokenEarlyExpiry) {
+		return t.token, nil
+	}
+
+	token, err := t.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	t.token = token
+	return token, nil
+}
+
+// bearerTransport sets the bearer token on every request and retries once on 401 if the token source
+// hands out a new token
+type bearerTransport struct {
+	base   http.RoundTripper
+	tokens *tokenCache
+}
+
+// RoundTrip implements http.RoundTripper
+func (b *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := b.tokens.get(false)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.roundTrip(req, token, false)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	fresh, err := b.tokens.get(true)
+	// A caching token source, like oauth2.ReuseTokenSource, returns the same token again
+	if err != nil || fresh.AccessToken == token.AccessToken {
+		return resp, nil
+	}
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return b.roundTrip(req, fresh, true)
+}
+
+// roundTrip sends req with token, rewind resends the body of an earlier attempt
+func (b *bearerTransport) roundTrip(req *http.Request, token *oauth2.Token, rewind bool) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	if rewind && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	r.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	return b.base.RoundTrip(r)
+}
+
+// clientCredentialsSource fetches tokens with the client credentials grant,
+// authenticating with either client secret or a signed client assertion.
+type clientCredentialsSource struct {
+	config     OidcConfig
+	signer     crypto.Signer
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	tokenURL string
+}
+
+// Token implements oauth2.TokenSource
+func (s *clientCredentialsSource) Token() (*oauth2.Token, error) {
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, s.httpClient)
+
+	tokenURL, err := s.endpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := clientcredentials.Config{
+		ClientID:     s.config.ClientID,
+		ClientSecret: s.config.ClientSecret,
+		TokenURL:     tokenURL,
+		Scopes:       s.config.Scopes,
+	}
+
+	if s.signer != nil {
+		assertion, err := s.clientAssertion(tokenURL)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientSecret = ""
+		cfg.AuthStyle = oauth2.AuthStyleInParams
+		cfg.EndpointParams = url.Values{
+			"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+			"client_assertion":      {assertion},
+		}
+	}
+
+	return cfg.Token(ctx)
+}
+
+// endpoint returns the token endpoint, discovered from the issuer unless TokenURL is set
+func (s *clientCredentialsSource) endpoint(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.config.TokenURL != "" {
+		return s.config.TokenURL, nil
+	}
+	if s.tokenURL != "" {
+		return s.tokenURL, nil
+	}
+
+	discoveryURL := strings.TrimSuffix(s.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("oidc discovery %s: %s", discoveryURL, resp.Status)
+	}
+
+	discovery := struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		return "", err
+	}
+	if discovery.TokenEndpoint == "" {
+		return "", ErrNoTokenEndpoint
+	}
+
+	s.tokenURL = discovery.TokenEndpoint
+	return s.tokenURL, nil
+}
+
+// clientAssertion returns a short lived JWT signed by the client's private key (RFC 7523)
+func (s *clientCredentialsSource) clientAssertion(audience string) (string, error) {
+	var alg string
+	switch key := s.signer.Public().(type) {
+	case *rsa.PublicKey:
+		alg = "RS256"
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return "", ErrUnsupportedKey
+		}
+		alg = "ES256"
+	default:
+		return "", ErrUnsupportedKey
+	}
+
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+
+	header := map[string]string{"alg": alg, "typ": "JWT"}
+	if s.config.KeyID != "" {
+		header["kid"] = s.config.KeyID
+	}
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss": s.config.ClientID,
+		"sub": s.config.ClientID,
+		"aud": audience,
+		"jti": hex.EncodeToString(jti),
+		"iat": now.Unix(),
+		"exp": now.Add(time.Minute).Unix(),
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	signature, err := s.signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+
+	if alg == "ES256" {
+		signature, err = ecdsaRawSignature(signature)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// ecdsaRawSignature converts an ASN.1 ECDSA P-256 signature to the r||s form used by JWS
+func ecdsaRawSignature(der []byte) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, 64)
+	sig.R.FillBytes(raw[:32])
+	sig.S.FillBytes(raw[32:])
+	return raw, nil
+}
+
+// parseSigner parses a PEM encoded PKCS#1, PKCS#8 or SEC 1 private key
+func parseSigner(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, ErrUnsupportedKey
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+	return signer, nil
+}
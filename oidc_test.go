@@ -0,0 +1,363 @@
+package goladok3
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+// mockTokenServer is a stand-in OIDC provider, it hands out token-1, token-2...
+type mockTokenServer struct {
+	server    *httptest.Server
+	expiresIn int
+	issued    int
+	publicKey crypto.PublicKey
+	t         *testing.T
+}
+
+func newMockTokenServer(t *testing.T, expiresIn int) *mockTokenServer {
+	m := &mockTokenServer{expiresIn: expiresIn, t: t}
+	mux := http.NewServeMux()
+	m.server = httptest.NewServer(mux)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"issuer": %q, "token_endpoint": %q}`, m.server.URL, m.server.URL+"/token")
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		assert.Equal(t, "ladok", r.Form.Get("scope"))
+
+		if m.publicKey != nil {
+			assert.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", r.Form.Get("client_assertion_type"))
+			m.verifyAssertion(r.Form.Get("client_assertion"))
+		} else {
+			id, secret, ok := r.BasicAuth()
+			if !ok || id != "test-client" || secret != "test-secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+
+		m.issued++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": %d}`, m.issued, m.expiresIn)
+	})
+
+	return m
+}
+
+func (m *mockTokenServer) verifyAssertion(assertion string) {
+	parts := strings.Split(assertion, ".")
+	if !assert.Len(m.t, parts, 3) {
+		return
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	assert.NoError(m.t, err)
+	claims := map[string]interface{}{}
+	assert.NoError(m.t, json.Unmarshal(claimsJSON, &claims))
+	assert.Equal(m.t, "test-client", claims["iss"])
+	assert.Equal(m.t, m.server.URL+"/token", claims["aud"])
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	assert.NoError(m.t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+	switch key := m.publicKey.(type) {
+	case *rsa.PublicKey:
+		assert.NoError(m.t, rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature))
+	case *ecdsa.PublicKey:
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		assert.True(m.t, ecdsa.Verify(key, digest[:], r, s))
+	}
+}
+
+// mockBearerEndpoint serves GetAnvandareAutentiserad, accepting only the tokens in valid
+func mockBearerEndpoint(t *testing.T, valid ...string) (*httptest.Server, *[]string) {
+	seen := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		seen = append(seen, token)
+		for _, v := range valid {
+			if token == v {
+				w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+				w.Write(ladokmocks.JSONKataloginformationAutentiserad)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	return server, &seen
+}
+
+func TestNewOIDCClientSecret(t *testing.T) {
+	tokenServer := newMockTokenServer(t, 3600)
+	defer tokenServer.server.Close()
+	ladok, seen := mockBearerEndpoint(t, "token-1")
+	defer ladok.Close()
+
+	client, err := NewOIDC(OidcConfig{
+		URL:          ladok.URL,
+		Issuer:       tokenServer.server.URL,
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		Scopes:       []string{"ladok"},
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for i := 0; i < 3; i++ {
+		_, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, tokenServer.issued)
+	assert.Equal(t, []string{"token-1", "token-1", "token-1"}, *seen)
+}
+
+func TestNewOIDCRefreshBeforeExpiry(t *testing.T) {
+	tokenServer := newMockTokenServer(t, 30)
+	defer tokenServer.server.Close()
+	ladok, seen := mockBearerEndpoint(t, "token-1", "token-2")
+	defer ladok.Close()
+
+	client, err := NewOIDC(OidcConfig{
+		URL:          ladok.URL,
+		TokenURL:     tokenServer.server.URL + "/token",
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		Scopes:       []string{"ladok"},
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for i := 0; i < 2; i++ {
+		_, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, tokenServer.issued)
+	assert.Equal(t, []string{"token-1", "token-2"}, *seen)
+}
+
+func TestNewOIDCRetryOn401(t *testing.T) {
+	tokenServer := newMockTokenServer(t, 3600)
+	defer tokenServer.server.Close()
+	ladok, seen := mockBearerEndpoint(t, "token-2")
+	defer ladok.Close()
+
+	client, err := NewOIDC(OidcConfig{
+		URL:          ladok.URL,
+		Issuer:       tokenServer.server.URL,
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		Scopes:       []string{"ladok"},
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_, _, err = client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"token-1", "token-2"}, *seen)
+
+	// token-2 is cached and still accepted
+	_, _, err = client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tokenServer.issued)
+
+	// the fresh token is rejected as well, the second 401 is returned to the caller
+	rejecting, seen := mockBearerEndpoint(t)
+	defer rejecting.Close()
+	client.url = rejecting.URL
+
+	_, _, err = client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.ErrorIs(t, err, ladoktypes.ErrUnauthorized)
+	assert.Equal(t, []string{"token-2", "token-3"}, *seen)
+}
+
+func TestNewOIDCNoRetryWithSameToken(t *testing.T) {
+	ladok, seen := mockBearerEndpoint(t)
+	defer ladok.Close()
+
+	client, err := NewOIDC(OidcConfig{
+		URL:         ladok.URL,
+		TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "user-token"}),
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_, _, err = client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.ErrorIs(t, err, ladoktypes.ErrUnauthorized)
+	assert.Equal(t, []string{"user-token"}, *seen)
+}
+
+func TestNewOIDCPrivateKeyJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	pkcs8DER, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	tts := []struct {
+		name      string
+		keyPEM    []byte
+		publicKey crypto.PublicKey
+	}{
+		{
+			name:      "RSA PKCS1",
+			keyPEM:    pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+			publicKey: &rsaKey.PublicKey,
+		},
+		{
+			name:      "RSA PKCS8",
+			keyPEM:    pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8DER}),
+			publicKey: &rsaKey.PublicKey,
+		},
+		{
+			name:      "ECDSA P-256",
+			keyPEM:    pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}),
+			publicKey: &ecKey.PublicKey,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenServer := newMockTokenServer(t, 3600)
+			defer tokenServer.server.Close()
+			tokenServer.publicKey = tt.publicKey
+			ladok, _ := mockBearerEndpoint(t, "token-1")
+			defer ladok.Close()
+
+			client, err := NewOIDC(OidcConfig{
+				URL:           ladok.URL,
+				Issuer:        tokenServer.server.URL,
+				ClientID:      "test-client",
+				PrivateKeyPEM: tt.keyPEM,
+				KeyID:         "test-kid",
+				Scopes:        []string{"ladok"},
+			})
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			_, _, err = client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+			assert.NoError(t, err)
+			assert.Equal(t, 1, tokenServer.issued)
+		})
+	}
+}
+
+func TestNewOIDCTokenSource(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/uppfoljning/feed/recent", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer user-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", ContentTypeAtomXML)
+		w.Write(ladokmocks.XMLFeedRecent)
+	})
+
+	client, err := NewOIDC(OidcConfig{
+		URL:         server.URL,
+		TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "user-token"}),
+		Environment: ladoktypes.EnvProdAPI,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	feed, _, err := client.Feed.Recent(context.TODO())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, ladokmocks.MockSuperFeed(4856), feed)
+}
+
+func TestNewOIDCConfig(t *testing.T) {
+	tts := []struct {
+		name    string
+		have    OidcConfig
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			have:    OidcConfig{},
+			wantErr: true,
+		},
+		{
+			name:    "no credentials",
+			have:    OidcConfig{URL: "https://api.ladok.se", Issuer: "https://idp.example.com", ClientID: "test-client"},
+			wantErr: true,
+		},
+		{
+			name:    "no issuer",
+			have:    OidcConfig{URL: "https://api.ladok.se", ClientID: "test-client", ClientSecret: "test-secret"},
+			wantErr: true,
+		},
+		{
+			name:    "bad environment",
+			have:    OidcConfig{URL: "https://api.ladok.se", TokenSource: oauth2.StaticTokenSource(&oauth2.Token{}), Environment: "Dev-API"},
+			wantErr: true,
+		},
+		{
+			name:    "bad private key",
+			have:    OidcConfig{URL: "https://api.ladok.se", Issuer: "https://idp.example.com", ClientID: "test-client", PrivateKeyPEM: []byte("not a key")},
+			wantErr: true,
+		},
+		{
+			name: "client secret",
+			have: OidcConfig{URL: "https://api.ladok.se", Issuer: "https://idp.example.com", ClientID: "test-client", ClientSecret: "test-secret"},
+		},
+		{
+			name: "token source",
+			have: OidcConfig{URL: "https://api.ladok.se", TokenSource: oauth2.StaticTokenSource(&oauth2.Token{})},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewOIDC(tt.have)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
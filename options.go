@@ -0,0 +1,216 @@
+package goladok3
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+var (
+	// ErrUnknownFormat if a format other than FormatJSON or FormatXML is given
+	ErrUnknownFormat = errors.New("Unknown format")
+)
+
+const (
+	// DefaultUserAgent is sent with every request unless WithUserAgent is used
+	DefaultUserAgent = "goladok3/0.0.15"
+	// DefaultRateLimit is the number of requests per second allowed by default, see WithRateLimit
+	DefaultRateLimit = 1
+	// DefaultRateBurst is the number of requests allowed at once by default, see WithRateLimit
+	DefaultRateBurst = 30
+)
+
+// Auth authenticates a client to ladok, X509Config and OidcConfig are Auth
+type Auth interface {
+	// settings returns the settings shared by all authentication methods
+	settings() clientSettings
+	// authenticate sets the HTTPClient of c, and the certificate store of x509 clients
+	authenticate(c *Client) error
+}
+
+// clientSettings are the fields of X509Config and OidcConfig that are not about authentication
+type clientSettings struct {
+	URL            string
+	BaseURLs       map[string]string
+	Environment    string
+	Format         string
+	ProxyURL       string
+	RetryPolicy    *RetryPolicy
+	Middleware     []Middleware
+	TracerProvider trace.TracerProvider
+	Propagator     propagation.TextMapPropagator
+	Metrics        Metrics
+	Logger         Logger
+	LogLevel       LogLevel
+	Cache          *CacheConfig
+}
+
+// Option changes a Client made by New or Clone
+type Option func(*Client) error
+
+// New creates a client authenticated by auth, X509Config or OidcConfig, and changed by opts
+func New(auth Auth, opts ...Option) (*Client, error) {
+	if err := Check(auth); err != nil {
+		return nil, err
+	}
+	settings := auth.settings()
+	if settings.Format == "" {
+		settings.Format = FormatJSON
+	}
+
+	c := &Client{
+		format:      settings.Format,
+		proxyURL:    settings.ProxyURL,
+		env:         settings.Environment,
+		retryPolicy: settings.RetryPolicy,
+		mediaTypes:  newMediaTypes(),
+		done:        make(chan struct{}),
+		closeOnce:   &sync.Once{},
+		rateLimit:   rate.NewLimiter(DefaultRateLimit, DefaultRateBurst),
+		userAgent:   DefaultUserAgent,
+		baseURLs:    settings.BaseURLs,
+		middleware:  append([]Middleware{}, settings.Middleware...),
+		logger:      settings.Logger,
+		logLevel:    settings.LogLevel,
+		metrics:     settings.Metrics,
+		cache:       newResponseCache(settings.Cache),
+	}
+
+	if err := auth.authenticate(c); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	env, _ := c.Environment()
+	baseURL, err := resolveBaseURL(settings.URL, env, settings.BaseURLs)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	c.url = baseURL
+	c.configureTracing(settings.TracerProvider, settings.Propagator)
+	if c.metrics == nil {
+		c.metrics = noMetrics{}
+	}
+
+	if err := c.apply(opts); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Clone returns a copy of c changed by opts, leaving c as it is. Media types registered on the copy are its own.
+// The copy shares certificate, connections, rate limiter, cache and metrics with c, unless replaced by opts.
+// The background certificate checks belong to c, Close of the copy leaves them running.
+func (c *Client) Clone(opts ...Option) (*Client, error) {
+	clone := *c
+	httpClient := *c.HTTPClient
+	clone.HTTPClient = &httpClient
+	clone.middleware = append([]Middleware{}, c.middleware...)
+	clone.mediaTypes = c.mediaTypes.clone()
+	clone.done = make(chan struct{})
+	clone.closeOnce = &sync.Once{}
+
+	if err := clone.apply(opts); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
+// apply runs opts and sets up what depends on them
+func (c *Client) apply(opts []Option) error {
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return err
+		}
+	}
+
+	c.roundTripper = c.chainMiddleware(withLogging(c.middleware, c.logger, c.logLevel))
+	c.initServices()
+	return nil
+}
+
+// WithRateLimit allows limit requests per second to ladok, with bursts of up to burst requests,
+// like WithRateLimit(rate.Every(time.Second), 30)
+func WithRateLimit(limit rate.Limit, burst int) Option {
+	return func(c *Client) error {
+		c.rateLimit = rate.NewLimiter(limit, burst)
+		return nil
+	}
+}
+
+// WithHTTPClient sends requests with httpClient. A nil Transport is replaced by the transport of the client,
+// so timeouts, redirects and cookies can be set while keeping the certificate or token authentication.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		hc := *httpClient
+		if hc.Transport == nil {
+			hc.Transport = c.HTTPClient.Transport
+		}
+		c.HTTPClient = &hc
+		return nil
+	}
+}
+
+// WithUserAgent sends userAgent instead of DefaultUserAgent
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithFormat requests FormatJSON or FormatXML representations from ladok
+func WithFormat(format string) Option {
+	return func(c *Client) error {
+		if format != FormatJSON && format != FormatXML {
+			return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+		}
+		c.format = format
+		return nil
+	}
+}
+
+// WithBaseURL sends requests to baseURL, which has to belong to the environment of the client, see DefaultBaseURLs
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		env, _ := c.Environment()
+		url, err := resolveBaseURL(baseURL, env, c.baseURLs)
+		if err != nil {
+			return err
+		}
+		c.url = url
+		return nil
+	}
+}
+
+// WithLogger logs every request to ladok at level with personal data redacted, see X509Config.Logger
+func WithLogger(logger Logger, level LogLevel) Option {
+	return func(c *Client) error {
+		c.logger = logger
+		c.logLevel = level
+		return nil
+	}
+}
+
+// WithMiddleware adds middleware inside the middleware already in use
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) error {
+		c.middleware = append(c.middleware, middleware...)
+		return nil
+	}
+}
+
+// WithRetryPolicy retries idempotent requests according to policy, nil means a single attempt
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) error {
+		c.retryPolicy = policy
+		return nil
+	}
+}
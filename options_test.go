@@ -0,0 +1,193 @@
+package goladok3
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+// mockOptionsServer replies to GetAnvandareAutentiserad and records the headers of the latest request
+func mockOptionsServer(t *testing.T, header *http.Header) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testURL(t, r, "/kataloginformation/anvandare/autentiserad")
+		*header = r.Header.Clone()
+		w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+		w.Write(ladokmocks.JSONKataloginformationAutentiserad)
+	}))
+}
+
+func TestNew(t *testing.T) {
+	var header http.Header
+	server := mockOptionsServer(t, &header)
+	defer server.Close()
+	certPEM, _, keyPEM, _ := ladokmocks.MockCertificateAndKey(t, ladoktypes.EnvIntTestAPI, 0, 100)
+
+	tts := []struct {
+		name          string
+		url           string
+		opts          []Option
+		wantErr       error
+		wantUserAgent string
+		wantAccept    string
+	}{
+		{
+			name:          "defaults",
+			url:           server.URL,
+			wantUserAgent: DefaultUserAgent,
+			wantAccept:    "application/vnd.ladok-kataloginformation+json",
+		},
+		{
+			name:          "user agent and format",
+			url:           server.URL,
+			opts:          []Option{WithUserAgent("sunet/1.0"), WithFormat(FormatXML)},
+			wantUserAgent: "sunet/1.0",
+			wantAccept:    "application/vnd.ladok-kataloginformation+xml",
+		},
+		{
+			name:          "base url",
+			url:           "https://ladok.example.com",
+			opts:          []Option{WithBaseURL(server.URL)},
+			wantUserAgent: DefaultUserAgent,
+			wantAccept:    "application/vnd.ladok-kataloginformation+json",
+		},
+		{
+			name:    "base url of another environment",
+			url:     server.URL,
+			opts:    []Option{WithBaseURL(DefaultBaseURLs[ladoktypes.EnvProdAPI])},
+			wantErr: ErrEnvironmentMismatch,
+		},
+		{
+			name:    "unknown format",
+			url:     server.URL,
+			opts:    []Option{WithFormat("yaml")},
+			wantErr: ErrUnknownFormat,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := New(X509Config{
+				URL:            tt.url,
+				CertificatePEM: certPEM,
+				PrivateKeyPEM:  keyPEM,
+			}, tt.opts...)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			defer client.Close()
+
+			_, _, err = client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantUserAgent, header.Get("User-Agent"))
+			assert.Equal(t, tt.wantAccept, header.Get("Accept"))
+		})
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	var header http.Header
+	server := mockOptionsServer(t, &header)
+	defer server.Close()
+
+	certPEM, _, keyPEM, _ := ladokmocks.MockCertificateAndKey(t, ladoktypes.EnvIntTestAPI, 0, 100)
+	cfg := X509Config{
+		URL:            server.URL,
+		CertificatePEM: certPEM,
+		PrivateKeyPEM:  keyPEM,
+	}
+	buf := &bytes.Buffer{}
+	client, err := New(cfg,
+		WithRateLimit(rate.Every(time.Minute), 2),
+		WithHTTPClient(&http.Client{Timeout: time.Minute}),
+		WithLogger(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})), LogRequests),
+	)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer client.Close()
+
+	assert.Equal(t, rate.Every(time.Minute), client.rateLimit.Limit())
+	assert.Equal(t, 2, client.rateLimit.Burst())
+	assert.Equal(t, time.Minute, client.HTTPClient.Timeout)
+	// A nil Transport keeps the client certificate
+	assert.NotNil(t, client.HTTPClient.Transport)
+
+	_, _, err = client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "GetAnvandareAutentiserad")
+}
+
+func TestNewX509Wrapper(t *testing.T) {
+	client := mockNewClient(t, ladoktypes.EnvIntTestAPI, "https://ladok.example.com")
+	defer client.Close()
+
+	assert.Equal(t, DefaultUserAgent, client.userAgent)
+	assert.Equal(t, rate.Limit(DefaultRateLimit), client.rateLimit.Limit())
+	assert.Equal(t, DefaultRateBurst, client.rateLimit.Burst())
+	assert.Equal(t, FormatJSON, client.format)
+}
+
+func TestClone(t *testing.T) {
+	var header http.Header
+	server := mockOptionsServer(t, &header)
+	defer server.Close()
+
+	client := mockNewClient(t, ladoktypes.EnvIntTestAPI, server.URL)
+	defer client.Close()
+
+	var endpoints []Endpoint
+	clone, err := client.Clone(
+		WithUserAgent("clone/1.0"),
+		WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				endpoint, _ := EndpointFromContext(req.Context())
+				endpoints = append(endpoints, endpoint)
+				return next.RoundTrip(req)
+			})
+		}),
+	)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.NotSame(t, client.HTTPClient, clone.HTTPClient)
+	assert.Same(t, client.HTTPClient.Transport, clone.HTTPClient.Transport)
+	assert.Same(t, clone, clone.Kataloginformation.client)
+	assert.Same(t, client, client.Kataloginformation.client)
+	assert.Same(t, client.rateLimit, clone.rateLimit)
+
+	_, _, err = clone.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "clone/1.0", header.Get("User-Agent"))
+	assert.Len(t, endpoints, 1)
+
+	_, _, err = client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultUserAgent, header.Get("User-Agent"))
+	assert.Len(t, endpoints, 1)
+
+	// Media types registered on the clone are its own
+	assert.NoError(t, clone.RegisterMediaType("application/cbor", decodeJSON))
+	_, err = clone.mediaTypes.decoder("application/cbor")
+	assert.NoError(t, err)
+	_, err = client.mediaTypes.decoder("application/cbor")
+	assert.ErrorIs(t, err, ladoktypes.ErrNoValidContentType)
+
+	_, err = client.Clone(WithFormat("yaml"))
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+	assert.Equal(t, FormatJSON, client.format)
+
+	// Close of a clone and its client is safe
+	assert.NoError(t, clone.Close())
+	assert.NoError(t, client.Close())
+}
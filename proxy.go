@@ -0,0 +1,137 @@
+package goladok3
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// proxyFunc returns the proxy function for the transport. An empty proxyURL keeps the
+// environment (HTTPS_PROXY, NO_PROXY) in charge, otherwise only ladok traffic goes through proxyURL,
+// except hosts matching noProxy.
+func proxyFunc(proxyURL string, noProxy []string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy url %q: scheme has to be http, https or socks5", u.Redacted())
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid proxy url %q: no host", u.Redacted())
+	}
+
+	bypass := newProxyBypass(noProxy)
+
+	return func(req *http.Request) (*url.URL, error) {
+		if bypass.match(req.URL) {
+			return nil, nil
+		}
+		return u, nil
+	}, nil
+}
+
+// defaultPorts are compared with the port of NoProxy entries when the URL has none
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// proxyBypass matches hosts in the NO_PROXY style, see X509Config.NoProxy
+type proxyBypass struct {
+	all      bool
+	networks []*net.IPNet
+	ips      []proxyBypassIP
+	domains  []proxyBypassDomain
+}
+
+type proxyBypassIP struct {
+	ip   net.IP
+	port string
+}
+
+type proxyBypassDomain struct {
+	domain string
+	port   string
+	// exact is set when the domain has no leading dot, it matches the domain itself as well as its subdomains
+	exact bool
+}
+
+func newProxyBypass(noProxy []string) *proxyBypass {
+	p := &proxyBypass{}
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			p.all = true
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			p.networks = append(p.networks, network)
+			continue
+		}
+
+		host, port, err := net.SplitHostPort(entry)
+		if err != nil {
+			host, port = entry, ""
+		}
+		if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+			p.ips = append(p.ips, proxyBypassIP{ip: ip, port: port})
+			continue
+		}
+
+		host = strings.TrimPrefix(host, "*")
+		p.domains = append(p.domains, proxyBypassDomain{
+			domain: strings.TrimPrefix(host, "."),
+			port:   port,
+			exact:  !strings.HasPrefix(host, "."),
+		})
+	}
+	return p
+}
+
+func (p *proxyBypass) match(u *url.URL) bool {
+	if p.all {
+		return true
+	}
+
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if port == "" {
+		port = defaultPorts[u.Scheme]
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		for _, network := range p.networks {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		for _, bypass := range p.ips {
+			if bypass.ip.Equal(ip) && (bypass.port == "" || bypass.port == port) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, d := range p.domains {
+		if d.port != "" && d.port != port {
+			continue
+		}
+		if strings.HasSuffix(host, "."+d.domain) || (d.exact && host == d.domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+package goladok3
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockConnectProxy is a local CONNECT proxy requiring basic auth
+type mockConnectProxy struct {
+	server *httptest.Server
+	mu     sync.Mutex
+	hosts  []string
+}
+
+func newMockConnectProxy(t *testing.T, user, password string) *mockConnectProxy {
+	p := &mockConnectProxy{}
+	want := "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+
+	p.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Proxy-Authorization") != want {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+
+		p.mu.Lock()
+		p.hosts = append(p.hosts, r.Host)
+		p.mu.Unlock()
+
+		upstream, err := net.Dial("tcp", r.Host)
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if !assert.NoError(t, err) {
+			upstream.Close()
+			return
+		}
+
+		go func() {
+			io.Copy(upstream, conn)
+			upstream.Close()
+		}()
+		io.Copy(conn, upstream)
+		conn.Close()
+	}))
+
+	return p
+}
+
+func (p *mockConnectProxy) seen() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string{}, p.hosts...)
+}
+
+func TestProxyURL(t *testing.T) {
+	ladok := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+		w.Write(ladokmocks.JSONKataloginformationAutentiserad)
+	}))
+	defer ladok.Close()
+	ladokHost := ladok.Listener.Addr().String()
+
+	proxy := newMockConnectProxy(t, "ladok", "s3cret")
+	defer proxy.server.Close()
+	proxyURL, _ := url.Parse(proxy.server.URL)
+
+	tts := []struct {
+		name      string
+		proxyURL  string
+		noProxy   []string
+		wantHosts []string
+		wantErr   bool
+	}{
+		{
+			name:      "authenticated proxy",
+			proxyURL:  "http://ladok:s3cret@" + proxyURL.Host,
+			wantHosts: []string{ladokHost},
+		},
+		{
+			name:     "wrong password",
+			proxyURL: "http://ladok:wrong@" + proxyURL.Host,
+			wantErr:  true,
+		},
+		{
+			name:      "bypassed by ip",
+			proxyURL:  "http://ladok:s3cret@" + proxyURL.Host,
+			noProxy:   []string{"127.0.0.1"},
+			wantHosts: []string{},
+		},
+		{
+			name:      "bypassed by cidr",
+			proxyURL:  "http://ladok:s3cret@" + proxyURL.Host,
+			noProxy:   []string{"10.0.0.0/8", "127.0.0.0/8"},
+			wantHosts: []string{},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy.mu.Lock()
+			proxy.hosts = []string{}
+			proxy.mu.Unlock()
+
+			certPEM, _, keyPEM, _ := ladokmocks.MockCertificateAndKey(t, ladoktypes.EnvProdAPI, 0, 100)
+			client, err := NewX509(X509Config{
+				URL:            ladok.URL,
+				CertificatePEM: certPEM,
+				PrivateKeyPEM:  keyPEM,
+				ServerCAPEM:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ladok.Certificate().Raw}),
+				ProxyURL:       tt.proxyURL,
+				NoProxy:        tt.noProxy,
+			})
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			_, _, err = client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHosts, proxy.seen())
+		})
+	}
+}
+
+func TestProxyURLValidation(t *testing.T) {
+	tts := []struct {
+		name     string
+		proxyURL string
+	}{
+		{
+			name:     "no scheme",
+			proxyURL: "proxy.example.com:3128",
+		},
+		{
+			name:     "unsupported scheme",
+			proxyURL: "ftp://proxy.example.com",
+		},
+		{
+			name:     "no host",
+			proxyURL: "http://",
+		},
+		{
+			name:     "broken",
+			proxyURL: "http://proxy example.com",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := proxyFunc(tt.proxyURL, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProxyBypass(t *testing.T) {
+	bypass := newProxyBypass([]string{"ladok.se", ".example.com", "api.test.se:8443", "api.prod.se:443", "192.168.1.10", "10.0.0.0/8", "172.16.0.1:443", " "})
+
+	tts := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://ladok.se", want: true},
+		{url: "https://api.ladok.se", want: true},
+		{url: "https://notladok.se", want: false},
+		{url: "https://example.com", want: false},
+		{url: "https://www.example.com", want: true},
+		{url: "https://api.test.se:8443", want: true},
+		{url: "https://api.test.se", want: false},
+		{url: "https://192.168.1.10:443", want: true},
+		{url: "https://192.168.1.11", want: false},
+		{url: "https://10.1.2.3", want: true},
+		{url: "https://API.LADOK.SE", want: true},
+		{url: "https://api.prod.se", want: true},
+		{url: "https://api.prod.se:443", want: true},
+		{url: "http://api.prod.se", want: false},
+		{url: "https://api.prod.se:8443", want: false},
+		{url: "https://172.16.0.1", want: true},
+		{url: "https://172.16.0.1:8443", want: false},
+		{url: "http://172.16.0.1", want: false},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.url, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, tt.want, bypass.match(u))
+		})
+	}
+
+	all := newProxyBypass([]string{"*"})
+	assert.True(t, all.match(&url.URL{Host: "api.ladok.se"}))
+}
@@ -0,0 +1,113 @@
+package goladok3
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redacted replaces personal data removed by Redact
+const redacted = "[REDACTED]"
+
+var (
+	// personnummerPattern matches personnummer and samordningsnummer, with or without century and separator
+	personnummerPattern = regexp.MustCompile(`\b(?:19|20)?\d{6}[-+]?\d{4}\b`)
+	// uuidPrefixPattern matches the first four segments of a UUID, whose last segment may look like a personnummer
+	uuidPrefixPattern = regexp.MustCompile(`[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-$`)
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+\-]+(?:@|%40)[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+	// personalFields are the parts of json keys and xml elements holding personal data
+	personalFields   = `personnummer|epost|email|telefon|mobil|utdelningsadress|careof|postnummer|postort`
+	jsonFieldPattern = regexp.MustCompile(`(?i)("[A-Za-z]*(?:` + personalFields + `)[A-Za-z]*"\s*:\s*)(?:"(?:[^"\\]|\\.)*"|-?\d+(?:\.\d+)?)`)
+	xmlFieldPattern  = regexp.MustCompile(`(?i)(<(?:[A-Za-z0-9]+:)?[A-Za-z]*(?:` + personalFields + `)[A-Za-z]*(?:\s[^>]*)?>)[^<]*(</)`)
+
+	jsonStringPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+)
+
+// Redact masks personnummer, email addresses, phone numbers and postal addresses in s,
+// which may be a URL or a json or xml body from ladok. Bodies stay valid json and xml,
+// numbers outside the personal fields are left as they are.
+func Redact(s string) string {
+	s = jsonFieldPattern.ReplaceAllString(s, `$1"`+redacted+`"`)
+	s = xmlFieldPattern.ReplaceAllString(s, `${1}`+redacted+`${2}`)
+
+	switch body := strings.TrimSpace(s); {
+	case strings.HasPrefix(body, "{"), strings.HasPrefix(body, "["):
+		return jsonStringPattern.ReplaceAllStringFunc(s, redactText)
+	case strings.HasPrefix(body, "<"):
+		return emailPattern.ReplaceAllString(redactXMLPersonnummer(s), redacted)
+	}
+	return redactText(s)
+}
+
+// redactText masks personnummer and email addresses in s
+func redactText(s string) string {
+	b := strings.Builder{}
+	last := 0
+	for _, loc := range personnummerMatches(s) {
+		b.WriteString(s[last:loc[0]])
+		b.WriteString(redacted)
+		last = loc[1]
+	}
+	b.WriteString(s[last:])
+	return emailPattern.ReplaceAllString(b.String(), redacted)
+}
+
+// redactXMLPersonnummer masks personnummer in an xml body, except element text that is only a number,
+// which would no longer decode into its numeric field
+func redactXMLPersonnummer(s string) string {
+	b := strings.Builder{}
+	last := 0
+	for _, loc := range personnummerMatches(s) {
+		match := s[loc[0]:loc[1]]
+		if strings.Trim(match, "0123456789") == "" &&
+			strings.HasSuffix(strings.TrimSpace(s[:loc[0]]), ">") &&
+			strings.HasPrefix(strings.TrimSpace(s[loc[1]:]), "<") {
+			continue
+		}
+		b.WriteString(s[last:loc[0]])
+		b.WriteString(redacted)
+		last = loc[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// personnummerMatches returns the locations of personnummerPattern in s that have a valid date
+// and are not the last segment of a UUID
+func personnummerMatches(s string) [][]int {
+	matches := [][]int{}
+	for _, loc := range personnummerPattern.FindAllStringIndex(s, -1) {
+		// The four UUID segments before a match are 24 characters
+		before := s[:loc[0]]
+		if len(before) > 24 {
+			before = before[len(before)-24:]
+		}
+		if validPersonnummerDate(s[loc[0]:loc[1]]) && !uuidPrefixPattern.MatchString(before) {
+			matches = append(matches, loc)
+		}
+	}
+	return matches
+}
+
+// validPersonnummerDate reports if the date of personnummer exists, the day of a samordningsnummer is 60 more
+func validPersonnummerDate(personnummer string) bool {
+	digits := strings.NewReplacer("-", "", "+", "").Replace(personnummer)
+	date := digits[:len(digits)-4]
+	layout := "060102"
+	if len(date) == 8 {
+		layout = "20060102"
+	}
+
+	day, err := strconv.Atoi(date[len(date)-2:])
+	if err != nil {
+		return false
+	}
+	if day > 60 {
+		date = fmt.Sprintf("%s%02d", date[:len(date)-2], day-60)
+	}
+	_, err = time.Parse(layout, date)
+	return err == nil
+}
@@ -0,0 +1,88 @@
+package goladok3
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	tts := []struct {
+		name string
+		have string
+		want string
+	}{
+		{
+			name: "personnummer url",
+			have: "https://api.ladok.se/studentinformation/student/personnummer/199611052383",
+			want: "https://api.ladok.se/studentinformation/student/personnummer/[REDACTED]",
+		},
+		{
+			name: "personnummer with separator",
+			have: "pnr 961105-2383 and 19961105+2383",
+			want: "pnr [REDACTED] and [REDACTED]",
+		},
+		{
+			name: "uid url",
+			have: "https://api.ladok.se/studentinformation/student/8e3ae52e-6bba-11ec-8a8e-dd4bd5f5e1ea",
+			want: "https://api.ladok.se/studentinformation/student/8e3ae52e-6bba-11ec-8a8e-dd4bd5f5e1ea",
+		},
+		{
+			name: "uid with a digit only segment",
+			have: "https://api.ladok.se/studentinformation/student/11111111-2222-0000-0000-200012345678",
+			want: "https://api.ladok.se/studentinformation/student/11111111-2222-0000-0000-200012345678",
+		},
+		{
+			name: "uid segment with a valid date",
+			have: "student 11111111-2222-0000-0000-199611052383",
+			want: "student 11111111-2222-0000-0000-199611052383",
+		},
+		{
+			name: "no valid date",
+			have: "id 200012345678 and 9613052383",
+			want: "id 200012345678 and 9613052383",
+		},
+		{
+			name: "samordningsnummer",
+			have: "snr 19961165-2383",
+			want: "snr [REDACTED]",
+		},
+		{
+			name: "email",
+			have: "mail student@example.com or student%40example.com",
+			want: "mail [REDACTED] or [REDACTED]",
+		},
+		{
+			name: "json fields",
+			have: `{"Personnummer": "199611052383", "Fornamn": "Test", "Epostadress":"a@b.se", "Telefonnummer": "070-123 45 67", "Postadresser": [{"Utdelningsadress": "Gatan 1", "Postnummer": "123 45", "Postort": "Staden", "CareOf": "c\"o"}], "Uid": "1"}`,
+			want: `{"Personnummer": "[REDACTED]", "Fornamn": "Test", "Epostadress":"[REDACTED]", "Telefonnummer": "[REDACTED]", "Postadresser": [{"Utdelningsadress": "[REDACTED]", "Postnummer": "[REDACTED]", "Postort": "[REDACTED]", "CareOf": "[REDACTED]"}], "Uid": "1"}`,
+		},
+		{
+			name: "xml elements",
+			have: `<si:Student><si:Personnummer>199611052383</si:Personnummer><si:Telefonnummer>+46 70 123 45 67</si:Telefonnummer><si:Postadress><si:Utdelningsadress>Gatan 1</si:Utdelningsadress></si:Postadress><si:Fornamn>Test</si:Fornamn></si:Student>`,
+			want: `<si:Student><si:Personnummer>[REDACTED]</si:Personnummer><si:Telefonnummer>[REDACTED]</si:Telefonnummer><si:Postadress><si:Utdelningsadress>[REDACTED]</si:Utdelningsadress></si:Postadress><si:Fornamn>Test</si:Fornamn></si:Student>`,
+		},
+		{
+			name: "json numbers",
+			have: `{"Id": 1650000000, "LarosateID": 199611052383, "Personnummer": 199611052383, "Meddelande": "student 199611052383 saknas"}`,
+			want: `{"Id": 1650000000, "LarosateID": 199611052383, "Personnummer": "[REDACTED]", "Meddelande": "student [REDACTED] saknas"}`,
+		},
+		{
+			name: "xml numbers",
+			have: `<si:Student><si:Id>1650000000</si:Id><si:Meddelande>student 19961105-2383 saknas</si:Meddelande><base:link uri="https://api.ladok.se/student/personnummer/199611052383"/></si:Student>`,
+			want: `<si:Student><si:Id>1650000000</si:Id><si:Meddelande>student [REDACTED] saknas</si:Meddelande><base:link uri="https://api.ladok.se/student/personnummer/[REDACTED]"/></si:Student>`,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Redact(tt.have)
+			assert.Equal(t, tt.want, got)
+			if strings.HasPrefix(tt.have, "{") {
+				assert.True(t, json.Valid([]byte(got)), got)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package goladok3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrUnknownService if no media type is known for the service
+	ErrUnknownService = errors.New("Unknown ladok service")
+)
+
+// rawReply receives the undecoded reply of DoRaw
+type rawReply struct {
+	body      []byte
+	mediaType string
+}
+
+// serviceAcceptHeader returns the Accept header of service, given as key or as URL path segment, in the client format,
+// or in the only format the service has, like the xml of feed
+func (c *Client) serviceAcceptHeader(service string) (string, error) {
+	formats, ok := ladokAcceptHeader[serviceKey(service)]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownService, service)
+	}
+	if accept, ok := formats[c.format]; ok {
+		return accept, nil
+	}
+	for _, accept := range formats {
+		return accept, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownService, service)
+}
+
+// Do makes a request to path of service, like "studentinformation" and "student/{uid}", for endpoints without
+// a method of their own. body, if not nil, is sent as the media type of the service in the client format,
+// a []byte body as it is. The reply is decoded into out, if not nil. Rate limiting, retries, middleware and
+// errors work as for the other methods, non-successful replies are returned as *ladoktypes.HTTPError.
+// service is the URL path segment, like "studiedeltagande", or the service name of Endpoint, like "studentdeltagande".
+func (c *Client) Do(ctx context.Context, service, method, path string, body, out interface{}) (*http.Response, error) {
+	accept, err := c.serviceAcceptHeader(service)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/%s", servicePath(service), strings.TrimPrefix(path, "/"))
+
+	return c.call(ctx, Endpoint{Service: serviceKey(service), Operation: "Do"}, accept, method, url, body, out)
+}
+
+// DoRaw is Do returning the reply body undecoded, together with its media type
+func (c *Client) DoRaw(ctx context.Context, service, method, path string, body interface{}) ([]byte, string, *http.Response, error) {
+	accept, err := c.serviceAcceptHeader(service)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	url := fmt.Sprintf("%s/%s", servicePath(service), strings.TrimPrefix(path, "/"))
+
+	reply := &rawReply{}
+	resp, err := c.call(ctx, Endpoint{Service: serviceKey(service), Operation: "DoRaw"}, accept, method, url, body, reply)
+	if err != nil {
+		return nil, "", resp, err
+	}
+	return reply.body, reply.mediaType, resp, nil
+}
+
+// readRaw reads the body of resp into reply
+func readRaw(resp *http.Response, reply *rawReply) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	reply.body = body
+	reply.mediaType, _, _ = mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return nil
+}
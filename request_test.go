@@ -0,0 +1,229 @@
+package goladok3
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDo(t *testing.T) {
+	uid := ladokmocks.Students[0].StudentUID
+
+	tts := []struct {
+		name       string
+		format     string
+		service    string
+		path       string
+		reply      []byte
+		status     int
+		wantAccept string
+		wantErr    error
+	}{
+		{
+			name:       "json",
+			format:     FormatJSON,
+			service:    "studentinformation",
+			path:       "student/" + uid,
+			reply:      ladokmocks.JSONStudentinformationStudent,
+			status:     200,
+			wantAccept: "application/vnd.ladok-studentinformation+json",
+		},
+		{
+			name:       "xml",
+			format:     FormatXML,
+			service:    "studentinformation",
+			path:       "/student/" + uid,
+			reply:      ladokmocks.XMLStudentinformationStudent,
+			status:     200,
+			wantAccept: "application/vnd.ladok-studentinformation+xml",
+		},
+		{
+			name:       "ladok error",
+			format:     FormatJSON,
+			service:    "studentinformation",
+			path:       "student/" + uid,
+			reply:      ladokmocks.JSONErrors500,
+			status:     500,
+			wantAccept: "application/vnd.ladok-studentinformation+json",
+			wantErr:    ladoktypes.ErrInternalServerError,
+		},
+		{
+			name:    "unknown service",
+			format:  FormatJSON,
+			service: "okand",
+			path:    "student/" + uid,
+			wantErr: ErrUnknownService,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAccept string
+			var gotEndpoint Endpoint
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAccept = r.Header.Get("Accept")
+				testURL(t, r, "/"+tt.service+"/student/"+uid)
+				if tt.format == FormatXML {
+					w.Header().Set("Content-Type", ContentTypeStudentinformationXML)
+				} else {
+					w.Header().Set("Content-Type", ContentTypeStudentinformationJSON)
+				}
+				w.WriteHeader(tt.status)
+				w.Write(tt.reply)
+			}))
+			defer server.Close()
+
+			client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL, func(cfg *X509Config) {
+				cfg.Format = tt.format
+				cfg.Middleware = []Middleware{func(next http.RoundTripper) http.RoundTripper {
+					return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+						gotEndpoint, _ = EndpointFromContext(req.Context())
+						return next.RoundTrip(req)
+					})
+				}}
+			})
+
+			student := &ladoktypes.Student{}
+			_, err := client.Do(context.TODO(), tt.service, http.MethodGet, tt.path, nil, student)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				if tt.status != 0 {
+					testLadokError(t, ladokmocks.Errors500, err)
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, tt.wantAccept, gotAccept)
+			assert.Equal(t, Endpoint{Service: tt.service, Operation: "Do"}, gotEndpoint)
+			assert.Equal(t, "11111111-2222-0000-0000-000000000000", student.UID)
+
+			body, mediaType, resp, err := client.DoRaw(context.TODO(), tt.service, http.MethodGet, tt.path, nil)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, tt.reply, body)
+			assert.Equal(t, tt.wantAccept, mediaType)
+			assert.Equal(t, Endpoint{Service: tt.service, Operation: "DoRaw"}, gotEndpoint)
+		})
+	}
+}
+
+func TestDoServicePath(t *testing.T) {
+	tts := []struct {
+		name    string
+		service string
+	}{
+		{name: "path segment", service: "studiedeltagande"},
+		{name: "service name", service: "studentdeltagande"},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotEndpoint Endpoint
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				testURL(t, r, "/studiedeltagande/tillfallesdeltagande/x")
+				assert.Equal(t, "application/vnd.ladok-studiedeltagande+json", r.Header.Get("Accept"))
+				w.Header().Set("Content-Type", ContentTypeStudiedeltagandeJSON)
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL, func(cfg *X509Config) {
+				cfg.Middleware = []Middleware{func(next http.RoundTripper) http.RoundTripper {
+					return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+						gotEndpoint, _ = EndpointFromContext(req.Context())
+						return next.RoundTrip(req)
+					})
+				}}
+			})
+
+			_, _, _, err := client.DoRaw(context.TODO(), tt.service, http.MethodGet, "tillfallesdeltagande/x", nil)
+			assert.NoError(t, err)
+			assert.Equal(t, Endpoint{Service: "studentdeltagande", Operation: "DoRaw"}, gotEndpoint)
+		})
+	}
+}
+
+func TestServiceAcceptHeader(t *testing.T) {
+	client := mockNewClient(t, ladoktypes.EnvProdAPI, "https://api.ladok.se")
+
+	got, err := client.serviceAcceptHeader("feed")
+	assert.NoError(t, err)
+	assert.Equal(t, "application/atom+xml", got)
+
+	got, err = client.serviceAcceptHeader("resultat")
+	assert.NoError(t, err)
+	assert.Equal(t, "application/vnd.ladok-resultat+json", got)
+}
+
+func TestDoWrite(t *testing.T) {
+	tts := []struct {
+		name            string
+		method          string
+		body            interface{}
+		status          int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "post",
+			method:          http.MethodPost,
+			body:            map[string]string{"Fornamn": "Anna"},
+			status:          http.StatusCreated,
+			wantContentType: "application/vnd.ladok-studentinformation+json;charset=UTF-8",
+			wantBody:        `{"Fornamn":"Anna"}`,
+		},
+		{
+			name:            "raw body",
+			method:          http.MethodPut,
+			body:            []byte(`{"Efternamn":"Svensson"}`),
+			status:          http.StatusOK,
+			wantContentType: "application/vnd.ladok-studentinformation+json;charset=UTF-8",
+			wantBody:        `{"Efternamn":"Svensson"}`,
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, tt.method)
+				assert.Equal(t, tt.wantContentType, r.Header.Get("Content-Type"))
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantBody, string(body))
+
+				if tt.status == http.StatusNoContent {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Header().Set("Content-Type", ContentTypeStudentinformationJSON)
+				w.WriteHeader(tt.status)
+				w.Write(ladokmocks.JSONStudentinformationStudent)
+			}))
+			defer server.Close()
+			client := mockNewClient(t, ladoktypes.EnvIntTestAPI, server.URL)
+
+			got := &ladoktypes.Student{}
+			resp, err := client.Do(context.TODO(), "studentinformation", tt.method, "student", tt.body, got)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status != http.StatusNoContent {
+				assert.Equal(t, ladokmocks.MockStudentinformationStudent(), got)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package goladok3
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how failed requests are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled for every following retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts. A reply asking for a longer wait with Retry-After
+	// is not retried but returned.
+	MaxBackoff time.Duration
+	// Jitter is the random fraction (0-1) added to or subtracted from each backoff
+	Jitter float64
+	// RetryOn is the list of http status codes that will be retried
+	RetryOn []int
+}
+
+// DefaultRetryPolicy is a sensible policy for batch jobs talking to ladok
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+	Jitter:         0.2,
+	RetryOn: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// maxAttempts returns the number of attempts allowed for method
+func (p *RetryPolicy) maxAttempts(method string) int {
+	if p == nil || p.MaxAttempts < 1 || !isIdempotent(method) {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// retryStatus reports if statusCode should be retried
+func (p *RetryPolicy) retryStatus(statusCode int) bool {
+	for _, code := range p.RetryOn {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the wait before attempt+1, retryAfter is used when it is longer.
+// It reports false if retryAfter is longer than MaxBackoff, as ladok would refuse an earlier retry.
+func (p *RetryPolicy) backoff(attempt int, retryAfter time.Duration) (time.Duration, bool) {
+	if p.MaxBackoff > 0 && retryAfter > p.MaxBackoff {
+		return 0, false
+	}
+
+	wait := float64(p.InitialBackoff) * math.Pow(2, float64(attempt-1))
+	if p.Jitter > 0 {
+		wait += wait * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	d := time.Duration(wait)
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if retryAfter > d {
+		d = retryAfter
+	}
+	return d, true
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses the Retry-After header, either delay-seconds or a http-date
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,208 @@
+package goladok3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		RetryOn:        DefaultRetryPolicy.RetryOn,
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tts := []struct {
+		name         string
+		statusCodes  []int
+		retryAfter   string
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name:         "ok first attempt",
+			statusCodes:  []int{200},
+			wantAttempts: 1,
+		},
+		{
+			name:         "ok after 503",
+			statusCodes:  []int{503, 200},
+			wantAttempts: 2,
+		},
+		{
+			name:         "ok after 429 and 502",
+			statusCodes:  []int{429, 502, 200},
+			wantAttempts: 3,
+		},
+		{
+			name:         "give up after max attempts",
+			statusCodes:  []int{503, 503, 503, 200},
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "ok after 429 with retry-after",
+			statusCodes:  []int{429, 200},
+			retryAfter:   "0",
+			wantAttempts: 2,
+		},
+		{
+			name:         "no retry when retry-after is beyond max backoff",
+			statusCodes:  []int{429, 200},
+			retryAfter:   "120",
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "no retry on 500",
+			statusCodes:  []int{500, 200},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			mux, server, client := mockSetup(t, ladoktypes.EnvProdAPI)
+			defer server.Close()
+			client.retryPolicy = mockRetryPolicy()
+
+			hits := 0
+			mux.HandleFunc("/kataloginformation/anvandare/autentiserad", func(w http.ResponseWriter, r *http.Request) {
+				statusCode := tt.statusCodes[hits]
+				hits++
+				w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(statusCode)
+				if statusCode == 200 {
+					w.Write(ladokmocks.JSONKataloginformationAutentiserad)
+					return
+				}
+				w.Write(ladokmocks.JSONErrors500)
+			})
+
+			_, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+			assert.Equal(t, tt.wantAttempts, hits)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			ladokError := &ladoktypes.LadokError{}
+			assert.True(t, errors.As(err, &ladokError))
+
+			retryError := &ladoktypes.RetryError{}
+			if tt.wantAttempts > 1 {
+				if assert.True(t, errors.As(err, &retryError)) {
+					assert.Equal(t, tt.wantAttempts, retryError.Attempts)
+				}
+			} else {
+				assert.False(t, errors.As(err, &retryError))
+			}
+		})
+	}
+}
+
+func TestRetryNotIdempotent(t *testing.T) {
+	mux, server, client := mockSetup(t, ladoktypes.EnvProdAPI)
+	defer server.Close()
+	client.retryPolicy = mockRetryPolicy()
+
+	hits := 0
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(503)
+	})
+
+	_, err := client.call(context.TODO(), Endpoint{}, "", http.MethodPost, "test", struct{}{}, nil)
+	assert.Error(t, err)
+	assert.Equal(t, 1, hits)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	tts := []struct {
+		name string
+		have string
+		want time.Duration
+	}{
+		{
+			name: "empty",
+			have: "",
+			want: 0,
+		},
+		{
+			name: "seconds",
+			have: "120",
+			want: 120 * time.Second,
+		},
+		{
+			name: "negative",
+			have: "-1",
+			want: 0,
+		},
+		{
+			name: "http-date",
+			have: now.Add(30 * time.Second).Format(http.TimeFormat),
+			want: 30 * time.Second,
+		},
+		{
+			name: "http-date in the past",
+			have: now.Add(-30 * time.Second).Format(http.TimeFormat),
+			want: 0,
+		},
+		{
+			name: "garbage",
+			have: "soon",
+			want: 0,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseRetryAfter(tt.have, now))
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := &RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+
+	tts := []struct {
+		name       string
+		attempt    int
+		retryAfter time.Duration
+		want       time.Duration
+		wantOK     bool
+	}{
+		{name: "first", attempt: 1, want: 100 * time.Millisecond, wantOK: true},
+		{name: "doubled", attempt: 3, want: 400 * time.Millisecond, wantOK: true},
+		{name: "capped", attempt: 10, want: time.Second, wantOK: true},
+		{name: "retry-after", attempt: 1, retryAfter: 500 * time.Millisecond, want: 500 * time.Millisecond, wantOK: true},
+		{name: "retry-after at cap", attempt: 10, retryAfter: time.Second, want: time.Second, wantOK: true},
+		{name: "retry-after beyond cap", attempt: 1, retryAfter: time.Minute, wantOK: false},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := policy.backoff(tt.attempt, tt.retryAfter)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package goladok3
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/masv3971/goladok3/ladoktypes"
+)
+
+var (
+	// ErrNoCertificatesInCAPEM if ServerCAPEM holds no certificates
+	ErrNoCertificatesInCAPEM = errors.New("No certificates found in server CA PEM")
+)
+
+// TLSOptions configures the TLS connection to ladok, zero values give secure defaults
+type TLSOptions struct {
+	// MinVersion defaults to tls.VersionTLS12, lower versions are refused
+	MinVersion uint16
+	// MaxVersion defaults to the highest version supported, TLS 1.3
+	MaxVersion uint16
+	// CipherSuites limits the TLS 1.2 cipher suites, TLS 1.3 suites are not configurable.
+	// Defaults to the secure suites of crypto/tls, suites from tls.InsecureCipherSuites are refused.
+	CipherSuites []uint16
+}
+
+// TransportOptions configures connection handling towards ladok, zero values give the defaults
+type TransportOptions struct {
+	// DialTimeout defaults to 30 seconds
+	DialTimeout time.Duration
+	// KeepAlive defaults to 30 seconds
+	KeepAlive time.Duration
+	// TLSHandshakeTimeout defaults to 30 seconds
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout is the time to wait for the response headers, no limit by default
+	ResponseHeaderTimeout time.Duration
+	// MaxIdleConns defaults to 100
+	MaxIdleConns int
+	// MaxIdleConnsPerHost defaults to 10
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout defaults to 90 seconds
+	IdleConnTimeout time.Duration
+	// DisableHTTP2 makes the client use HTTP/1.1 only
+	DisableHTTP2 bool
+}
+
+// newTLSConfig returns a client tls.Config from opts
+func newTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	if opts.MinVersion == 0 {
+		opts.MinVersion = tls.VersionTLS12
+	}
+	if opts.MinVersion < tls.VersionTLS12 {
+		return nil, fmt.Errorf("tls min version %s is not allowed, use TLS 1.2 or later", tls.VersionName(opts.MinVersion))
+	}
+	if opts.MaxVersion != 0 && opts.MaxVersion < opts.MinVersion {
+		return nil, fmt.Errorf("tls max version %s is lower than min version %s", tls.VersionName(opts.MaxVersion), tls.VersionName(opts.MinVersion))
+	}
+
+	secure := map[uint16]bool{}
+	for _, suite := range tls.CipherSuites() {
+		secure[suite.ID] = true
+	}
+	for _, suite := range opts.CipherSuites {
+		if !secure[suite] {
+			return nil, fmt.Errorf("cipher suite %s is not allowed", tls.CipherSuiteName(suite))
+		}
+	}
+
+	return &tls.Config{
+		Rand:         rand.Reader,
+		MinVersion:   opts.MinVersion,
+		MaxVersion:   opts.MaxVersion,
+		CipherSuites: opts.CipherSuites,
+	}, nil
+}
+
+// newTransport returns a http.Transport using tlsCfg, opts and proxy
+func newTransport(tlsCfg *tls.Config, opts TransportOptions, proxy func(*http.Request) (*url.URL, error)) *http.Transport {
+	if opts.DialTimeout == 0 {
+		opts.DialTimeout = 30 * time.Second
+	}
+	if opts.KeepAlive == 0 {
+		opts.KeepAlive = 30 * time.Second
+	}
+	if opts.TLSHandshakeTimeout == 0 {
+		opts.TLSHandshakeTimeout = 30 * time.Second
+	}
+	if opts.MaxIdleConns == 0 {
+		opts.MaxIdleConns = 100
+	}
+	if opts.MaxIdleConnsPerHost == 0 {
+		opts.MaxIdleConnsPerHost = 10
+	}
+	if opts.IdleConnTimeout == 0 {
+		opts.IdleConnTimeout = 90 * time.Second
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   opts.DialTimeout,
+		KeepAlive: opts.KeepAlive,
+	}
+
+	transport := &http.Transport{
+		TLSClientConfig:       tlsCfg,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   opts.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
+		MaxIdleConns:          opts.MaxIdleConns,
+		MaxIdleConnsPerHost:   opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:       opts.IdleConnTimeout,
+		ForceAttemptHTTP2:     !opts.DisableHTTP2,
+		Proxy:                 proxy,
+	}
+	if opts.DisableHTTP2 {
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	return transport
+}
+
+// configureServerVerification sets RootCAs from chainPEM and adds SPKI pinning to tlsCfg
+func (c *Client) configureServerVerification(tlsCfg *tls.Config, pins []string) error {
+	if c.chainPEM != nil {
+		c.chain = x509.NewCertPool()
+		if !c.chain.AppendCertsFromPEM(c.chainPEM) {
+			return ErrNoCertificatesInCAPEM
+		}
+		tlsCfg.RootCAs = c.chain
+	}
+
+	if len(pins) == 0 {
+		return nil
+	}
+
+	pinned := make(map[string]bool, len(pins))
+	for _, pin := range pins {
+		raw, err := base64.StdEncoding.DecodeString(pin)
+		if err != nil || len(raw) != sha256.Size {
+			return fmt.Errorf("invalid SPKI pin %q, want base64 encoded sha256", pin)
+		}
+		pinned[pin] = true
+	}
+
+	tlsCfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		for _, chain := range cs.VerifiedChains {
+			for _, cert := range chain {
+				if pinned[SPKIPin(cert)] {
+					return nil
+				}
+			}
+		}
+		return &ladoktypes.ServerCertificateError{
+			Host:   cs.ServerName,
+			Reason: "no certificate in the chain matches the pinned public keys",
+		}
+	}
+
+	return nil
+}
+
+// SPKIPin returns the base64 encoded sha256 of the certificate's SubjectPublicKeyInfo,
+// the format used by X509Config.ServerSPKIPins.
+func SPKIPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// serverCertificateError turns a failed verification of ladok's certificate into a ServerCertificateError
+func serverCertificateError(host string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	serverCertificateError := &ladoktypes.ServerCertificateError{}
+	if errors.As(err, &serverCertificateError) {
+		if serverCertificateError.Host == "" {
+			serverCertificateError.Host = host
+		}
+		return serverCertificateError
+	}
+
+	verificationError := &tls.CertificateVerificationError{}
+	if errors.As(err, &verificationError) {
+		reason := "verification failed"
+		var (
+			unknownAuthority x509.UnknownAuthorityError
+			hostname         x509.HostnameError
+			invalid          x509.CertificateInvalidError
+		)
+		switch {
+		case errors.As(err, &unknownAuthority):
+			reason = "signed by unknown authority"
+		case errors.As(err, &hostname):
+			reason = "hostname mismatch"
+		case errors.As(err, &invalid):
+			reason = "invalid certificate"
+		}
+		return &ladoktypes.ServerCertificateError{
+			Host:   host,
+			Reason: reason,
+			Err:    verificationError,
+		}
+	}
+
+	return err
+}
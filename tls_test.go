@@ -0,0 +1,246 @@
+package goladok3
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockTLSServer(t *testing.T) (*httptest.Server, []byte) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+		w.Write(ladokmocks.JSONKataloginformationAutentiserad)
+	}))
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	return server, caPEM
+}
+
+func TestServerVerification(t *testing.T) {
+	server, caPEM := mockTLSServer(t)
+	defer server.Close()
+
+	_, otherCert, _, _ := ladokmocks.MockCertificateAndKey(t, ladoktypes.EnvProdAPI, 0, 10)
+
+	tts := []struct {
+		name       string
+		caPEM      []byte
+		pins       []string
+		wantReason string
+	}{
+		{
+			name:  "CA bundle",
+			caPEM: caPEM,
+		},
+		{
+			name:       "system trust store",
+			wantReason: "signed by unknown authority",
+		},
+		{
+			name:  "CA bundle and matching pin",
+			caPEM: caPEM,
+			pins:  []string{SPKIPin(otherCert), SPKIPin(server.Certificate())},
+		},
+		{
+			name:       "CA bundle and wrong pin",
+			caPEM:      caPEM,
+			pins:       []string{SPKIPin(otherCert)},
+			wantReason: "no certificate in the chain matches the pinned public keys",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL, func(cfg *X509Config) {
+				cfg.ServerCAPEM = tt.caPEM
+				cfg.ServerSPKIPins = tt.pins
+				cfg.RetryPolicy = mockRetryPolicy()
+			})
+
+			_, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+			if tt.wantReason == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			serverCertificateError := &ladoktypes.ServerCertificateError{}
+			if assert.ErrorAs(t, err, &serverCertificateError) {
+				assert.Equal(t, tt.wantReason, serverCertificateError.Reason)
+				assert.Equal(t, "127.0.0.1", serverCertificateError.Host)
+			}
+
+			retryError := &ladoktypes.RetryError{}
+			assert.False(t, errors.As(err, &retryError), "verification errors should not be retried")
+		})
+	}
+}
+
+func TestServerVerificationConfig(t *testing.T) {
+	tts := []struct {
+		name  string
+		caPEM []byte
+		pins  []string
+	}{
+		{
+			name:  "no certificates in CA PEM",
+			caPEM: []byte("not a certificate"),
+		},
+		{
+			name: "pin not base64",
+			pins: []string{"not base64!"},
+		},
+		{
+			name: "pin not sha256",
+			pins: []string{"dGVzdA=="},
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			certPEM, _, keyPEM, _ := ladokmocks.MockCertificateAndKey(t, ladoktypes.EnvProdAPI, 0, 100)
+			_, err := NewX509(X509Config{
+				URL:            "https://api.ladok.se",
+				CertificatePEM: certPEM,
+				PrivateKeyPEM:  keyPEM,
+				ServerCAPEM:    tt.caPEM,
+				ServerSPKIPins: tt.pins,
+			})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	tts := []struct {
+		name    string
+		have    TLSOptions
+		want    uint16
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			have: TLSOptions{},
+			want: tls.VersionTLS12,
+		},
+		{
+			name: "TLS 1.3 only",
+			have: TLSOptions{MinVersion: tls.VersionTLS13},
+			want: tls.VersionTLS13,
+		},
+		{
+			name:    "TLS 1.0",
+			have:    TLSOptions{MinVersion: tls.VersionTLS10},
+			wantErr: true,
+		},
+		{
+			name:    "max lower than min",
+			have:    TLSOptions{MinVersion: tls.VersionTLS13, MaxVersion: tls.VersionTLS12},
+			wantErr: true,
+		},
+		{
+			name:    "insecure cipher suite",
+			have:    TLSOptions{CipherSuites: []uint16{tls.TLS_RSA_WITH_RC4_128_SHA}},
+			wantErr: true,
+		},
+		{
+			name: "secure cipher suite",
+			have: TLSOptions{CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}},
+			want: tls.VersionTLS12,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTLSConfig(tt.have)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, tt.want, got.MinVersion)
+			assert.Equal(t, tt.have.CipherSuites, got.CipherSuites)
+		})
+	}
+}
+
+func TestTLSOptions(t *testing.T) {
+	tts := []struct {
+		name           string
+		serverTLS      *tls.Config
+		serverHTTP2    bool
+		tlsOptions     TLSOptions
+		transport      TransportOptions
+		wantVersion    uint16
+		wantProtoMajor int
+	}{
+		{
+			name:           "TLS 1.3 server",
+			serverTLS:      &tls.Config{MinVersion: tls.VersionTLS13},
+			wantVersion:    tls.VersionTLS13,
+			wantProtoMajor: 1,
+		},
+		{
+			name:           "TLS 1.2 server with configured suite",
+			serverTLS:      &tls.Config{MaxVersion: tls.VersionTLS12},
+			tlsOptions:     TLSOptions{CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}},
+			wantVersion:    tls.VersionTLS12,
+			wantProtoMajor: 1,
+		},
+		{
+			name:           "HTTP/2",
+			serverTLS:      &tls.Config{},
+			serverHTTP2:    true,
+			wantVersion:    tls.VersionTLS13,
+			wantProtoMajor: 2,
+		},
+		{
+			name:           "HTTP/2 disabled",
+			serverTLS:      &tls.Config{},
+			serverHTTP2:    true,
+			transport:      TransportOptions{DisableHTTP2: true},
+			wantVersion:    tls.VersionTLS13,
+			wantProtoMajor: 1,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotVersion    uint16
+				gotProtoMajor int
+			)
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotVersion = r.TLS.Version
+				gotProtoMajor = r.ProtoMajor
+				w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+				w.Write(ladokmocks.JSONKataloginformationAutentiserad)
+			}))
+			server.TLS = tt.serverTLS
+			server.EnableHTTP2 = tt.serverHTTP2
+			server.StartTLS()
+			defer server.Close()
+
+			client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL, func(cfg *X509Config) {
+				cfg.ServerCAPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+				cfg.TLS = tt.tlsOptions
+				cfg.Transport = tt.transport
+			})
+
+			_, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, tt.wantVersion, gotVersion)
+			assert.Equal(t, tt.wantProtoMajor, gotProtoMajor)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package goladok3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/masv3971/goladok3/ladoktypes"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/masv3971/goladok3"
+
+// Span attributes set by the client. URLs, bodies and ladok messages are never recorded,
+// since they may hold personnummer and other personal data.
+const (
+	attributeService       = attribute.Key("ladok.service")
+	attributeOperation     = attribute.Key("ladok.operation")
+	attributeMethod        = attribute.Key("http.request.method")
+	attributeStatusCode    = attribute.Key("http.response.status_code")
+	attributeAttempts      = attribute.Key("ladok.attempts")
+	attributeRateLimitWait = attribute.Key("ladok.rate_limit.wait_seconds")
+	attributeFelUID        = attribute.Key("ladok.error.fel_uid")
+	attributeDetaljkod     = attribute.Key("ladok.error.detaljkod")
+	attributeFeedID        = attribute.Key("ladok.feed.id")
+	attributeFeedEvents    = attribute.Key("ladok.feed.events")
+)
+
+// configureTracing sets the tracer of the client, tracing is disabled if tracerProvider is nil.
+// The W3C trace context is propagated to ladok unless another propagator is given.
+func (c *Client) configureTracing(tracerProvider trace.TracerProvider, propagator propagation.TextMapPropagator) {
+	if tracerProvider == nil {
+		c.tracer = noop.NewTracerProvider().Tracer(tracerName)
+		c.propagator = propagator
+		return
+	}
+
+	c.tracer = tracerProvider.Tracer(tracerName)
+	if propagator == nil {
+		propagator = propagation.TraceContext{}
+	}
+	c.propagator = propagator
+}
+
+// startSpan starts a client span for a request to endpoint
+func (c *Client) startSpan(ctx context.Context, endpoint Endpoint, method string) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, endpoint.Service+"."+endpoint.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attributeService.String(endpoint.Service),
+			attributeOperation.String(endpoint.Operation),
+			attributeMethod.String(method),
+		),
+	)
+}
+
+// injectTraceContext adds the trace context headers of ctx to req
+func (c *Client) injectTraceContext(ctx context.Context, req *http.Request) {
+	if c.propagator != nil {
+		c.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	}
+}
+
+// endSpan records the outcome of a request on span and ends it
+func endSpan(span trace.Span, resp *http.Response, err error) {
+	defer span.End()
+
+	if resp != nil {
+		span.SetAttributes(attributeStatusCode.Int(resp.StatusCode))
+	}
+	if err == nil {
+		return
+	}
+
+	ladokError := &ladoktypes.LadokError{}
+	if errors.As(err, &ladokError) {
+		span.SetAttributes(
+			attributeFelUID.String(ladokError.FelUID),
+			attributeDetaljkod.String(ladokError.Detaljkod),
+		)
+	}
+	span.SetStatus(codes.Error, spanErrorDescription(err))
+}
+
+// spanErrorDescription describes err without URLs or ladok messages
+func spanErrorDescription(err error) string {
+	httpError := &ladoktypes.HTTPError{}
+	if errors.As(err, &httpError) {
+		return http.StatusText(httpError.StatusCode)
+	}
+	urlError := &url.Error{}
+	if errors.As(err, &urlError) {
+		return urlError.Op + ": " + urlError.Err.Error()
+	}
+	serverCertificateError := &ladoktypes.ServerCertificateError{}
+	if errors.As(err, &serverCertificateError) {
+		return "server certificate: " + serverCertificateError.Reason
+	}
+	return err.Error()
+}
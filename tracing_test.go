@@ -0,0 +1,153 @@
+package goladok3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/masv3971/goladok3/ladokmocks"
+	"github.com/masv3971/goladok3/ladoktypes"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	return attributes
+}
+
+func TestTracing(t *testing.T) {
+	personnummer := ladokmocks.Students[0].Personnummer
+
+	tts := []struct {
+		name          string
+		statusCode    int
+		reply         []byte
+		wantFelUID    string
+		wantDetaljkod string
+	}{
+		{
+			name:       "ok",
+			statusCode: 200,
+			reply:      ladokmocks.JSONStudentinformationStudent,
+		},
+		{
+			name:          "ladok error",
+			statusCode:    500,
+			reply:         ladokmocks.JSONErrors500,
+			wantFelUID:    ladokmocks.Errors500.FelUID,
+			wantDetaljkod: ladokmocks.Errors500.Detaljkod,
+		},
+	}
+
+	for _, tt := range tts {
+		t.Run(tt.name, func(t *testing.T) {
+			var traceparent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				traceparent = r.Header.Get("traceparent")
+				w.Header().Set("Content-Type", ContentTypeStudentinformationJSON)
+				w.WriteHeader(tt.statusCode)
+				w.Write(tt.reply)
+			}))
+			defer server.Close()
+
+			recorder := tracetest.NewSpanRecorder()
+			client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL, func(cfg *X509Config) {
+				cfg.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			})
+			_, _, err := client.Studentinformation.GetStudent(context.TODO(), &GetStudentReq{Personnummer: personnummer})
+			if tt.statusCode != 200 {
+				assert.Error(t, err)
+			}
+
+			spans := recorder.Ended()
+			if !assert.Len(t, spans, 1) {
+				t.FailNow()
+			}
+			span := spans[0]
+			assert.Equal(t, "studentinformation.GetStudent", span.Name())
+
+			// W3C trace context of the span is sent to ladok
+			assert.Equal(t, fmt.Sprintf("00-%s-%s-01", span.SpanContext().TraceID(), span.SpanContext().SpanID()), traceparent)
+
+			attributes := spanAttributes(span)
+			assert.Equal(t, "studentinformation", attributes[attributeService].AsString())
+			assert.Equal(t, "GetStudent", attributes[attributeOperation].AsString())
+			assert.Equal(t, "GET", attributes[attributeMethod].AsString())
+			assert.Equal(t, int64(tt.statusCode), attributes[attributeStatusCode].AsInt64())
+			assert.Equal(t, int64(1), attributes[attributeAttempts].AsInt64())
+			assert.Contains(t, attributes, attributeRateLimitWait)
+
+			if tt.wantFelUID != "" {
+				assert.Equal(t, tt.wantFelUID, attributes[attributeFelUID].AsString())
+				assert.Equal(t, tt.wantDetaljkod, attributes[attributeDetaljkod].AsString())
+				assert.Equal(t, codes.Error, span.Status().Code)
+			} else {
+				assert.NotContains(t, attributes, attributeFelUID)
+				assert.Equal(t, codes.Unset, span.Status().Code)
+			}
+
+			// No personal data in the span
+			assert.NotContains(t, span.Name(), personnummer)
+			assert.NotContains(t, span.Status().Description, personnummer)
+			for _, kv := range span.Attributes() {
+				assert.NotContains(t, kv.Value.Emit(), personnummer, string(kv.Key))
+			}
+		})
+	}
+}
+
+func TestTracingFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeAtomXML)
+		w.Write(ladokmocks.XMLFeedRecent)
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL, func(cfg *X509Config) {
+		cfg.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	})
+	feed, _, err := client.Feed.Recent(context.TODO())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	names := []string{}
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+		for _, kv := range span.Attributes() {
+			assert.False(t, strings.Contains(kv.Value.Emit(), "197001014622"), string(kv.Key))
+		}
+		if span.Name() == "feed.Parse" {
+			attributes := spanAttributes(span)
+			assert.Equal(t, int64(feed.ID), attributes[attributeFeedID].AsInt64())
+			assert.Equal(t, int64(len(feed.SuperEvents)), attributes[attributeFeedEvents].AsInt64())
+		}
+	}
+	assert.Equal(t, []string{"feed.Recent", "feed.Parse"}, names)
+}
+
+func TestTracingDisabled(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Header().Set("Content-Type", ContentTypeKataloginformationJSON)
+		w.Write(ladokmocks.JSONKataloginformationAutentiserad)
+	}))
+	defer server.Close()
+
+	client := mockNewClient(t, ladoktypes.EnvProdAPI, server.URL)
+	_, _, err := client.Kataloginformation.GetAnvandareAutentiserad(context.TODO())
+	assert.NoError(t, err)
+	assert.Empty(t, traceparent)
+}